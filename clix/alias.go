@@ -0,0 +1,32 @@
+package clix
+
+// AliasStrategy makes the alias of the flag name as Naming.Alias.
+// The empty string means that the flag has no alias.
+type AliasStrategy func(name string) string
+
+var (
+	// AliasInitials is the strategy of ShortFlagName.
+	// It is the default strategy of Naming.
+	AliasInitials AliasStrategy = ShortFlagName
+
+	// AliasNone is the strategy that makes no alias.
+	AliasNone AliasStrategy = func(string) string { return "" }
+)
+
+// AliasTable returns the strategy that looks up the alias of the name in
+// table, or calls fallback if the name is not in table.
+// If fallback is nil, the names not in table have no alias.
+//
+//	e.g.
+//	AliasTable(map[string]string{"tls-ca": "tlsca"}, AliasInitials)
+func AliasTable(table map[string]string, fallback AliasStrategy) AliasStrategy {
+	if fallback == nil {
+		fallback = AliasNone
+	}
+	return func(name string) string {
+		if alias, ok := table[name]; ok {
+			return alias
+		}
+		return fallback(name)
+	}
+}
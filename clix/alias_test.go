@@ -0,0 +1,87 @@
+package clix_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+)
+
+func TestNaming_Alias(t *testing.T) {
+	prefix := clix.FlagPrefix("CLIX_TEST_ALIAS_")
+
+	cases := []struct {
+		Strategy clix.AliasStrategy
+		Name     string
+		Want     *clix.FlagName
+	}{
+		{
+			nil,
+			"tls-cert",
+			&clix.FlagName{
+				Name:    "server-tls-cert",
+				Aliases: []string{"server-tc"},
+				EnvVars: []string{"CLIX_TEST_ALIAS_SERVER_TLS_CERT", "CLIX_TEST_ALIAS_SERVER_TC"},
+			},
+		},
+
+		{
+			clix.AliasNone,
+			"tls-cert",
+			&clix.FlagName{
+				Name:    "server-tls-cert",
+				EnvVars: []string{"CLIX_TEST_ALIAS_SERVER_TLS_CERT"},
+			},
+		},
+
+		{
+			clix.AliasTable(map[string]string{"tls-ca": "tlsca"}, clix.AliasInitials),
+			"tls-ca",
+			&clix.FlagName{
+				Name:    "server-tls-ca",
+				Aliases: []string{"server-tlsca"},
+				EnvVars: []string{"CLIX_TEST_ALIAS_SERVER_TLS_CA", "CLIX_TEST_ALIAS_SERVER_TLSCA"},
+			},
+		},
+
+		{
+			clix.AliasTable(map[string]string{"tls-ca": "tlsca"}, clix.AliasInitials),
+			"tls-cert",
+			&clix.FlagName{
+				Name:    "server-tls-cert",
+				Aliases: []string{"server-tc"},
+				EnvVars: []string{"CLIX_TEST_ALIAS_SERVER_TLS_CERT", "CLIX_TEST_ALIAS_SERVER_TC"},
+			},
+		},
+
+		{
+			clix.AliasTable(map[string]string{"tls-ca": "tlsca"}, nil),
+			"tls-cert",
+			&clix.FlagName{
+				Name:    "server-tls-cert",
+				EnvVars: []string{"CLIX_TEST_ALIAS_SERVER_TLS_CERT"},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		got := clix.Naming{Prefix: prefix, Alias: c.Strategy}.NewFlagName("server", c.Name)
+		if diff := cmp.Diff(c.Want, got); diff != "" {
+			t.Errorf("[%d] -want +got\n%s", i, diff)
+		}
+	}
+}
+
+func TestNaming_NewFlagNameDefault(t *testing.T) {
+	prefix := clix.FlagPrefix("CLIX_TEST_ALIAS_")
+
+	got := clix.Naming{Prefix: prefix}.NewFlagNameDefault("", "tls-cert", "tlscrt")
+	if diff := cmp.Diff([]string{"tlscrt"}, got.Aliases); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+
+	got = clix.Naming{Prefix: prefix, Alias: clix.AliasNone}.NewFlagNameDefault("", "tls-cert", "tlscrt")
+	if len(got.Aliases) != 0 {
+		t.Errorf("Aliases=%v, want none", got.Aliases)
+	}
+}
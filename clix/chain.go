@@ -1,6 +1,14 @@
 package clix
 
-import "github.com/urfave/cli/v2"
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/urfave/cli/v2"
+)
 
 // Chain returns a function of type `func(*cli.Context) error`
 // in which each fn is called in order.
@@ -14,3 +22,118 @@ func Chain(fn ...func(*cli.Context) error) func(*cli.Context) error {
 		return nil
 	}
 }
+
+// ChainAll returns a function of type `func(*cli.Context) error`
+// in which each fn is called in order even if some of them fail.
+// The errors are returned together as Errors.
+// A panic in fn is recovered as *PanicError.
+func ChainAll(fn ...func(*cli.Context) error) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		var errs Errors
+		for _, f := range fn {
+			errs = appendErrors(errs, callHook(f, c))
+		}
+		return errs.Err()
+	}
+}
+
+// When returns a function of type `func(*cli.Context) error`
+// in which each fn is called in order as Chain only if pred returns true.
+// A panic in pred or fn is recovered as *PanicError.
+func When(pred func(*cli.Context) bool, fn ...func(*cli.Context) error) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if ok, err := callPred(pred, c); err != nil || !ok {
+			return err
+		}
+		for _, f := range fn {
+			if err := callHook(f, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Parallel returns a function of type `func(*cli.Context) error`
+// in which each fn is called concurrently, and waits for all of them.
+// The errors are returned together as Errors in the order of fn.
+// A panic in fn is recovered as *PanicError.
+func Parallel(fn ...func(*cli.Context) error) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		results := make([]error, len(fn))
+		var wg sync.WaitGroup
+		wg.Add(len(fn))
+		for i, f := range fn {
+			go func(i int, f func(*cli.Context) error) {
+				defer wg.Done()
+				results[i] = callHook(f, c)
+			}(i, f)
+		}
+		wg.Wait()
+
+		var errs Errors
+		for _, err := range results {
+			errs = appendErrors(errs, err)
+		}
+		return errs.Err()
+	}
+}
+
+// Finally returns a function of type `func(*cli.Context) error`
+// in which fn is called, then final is called even if fn fails or panics.
+// The errors are returned together as Errors.
+// A panic in fn or final is recovered as *PanicError.
+func Finally(fn, final func(*cli.Context) error) func(*cli.Context) error {
+	return ChainAll(fn, final)
+}
+
+// PanicError represents a panic recovered in a hook.
+type PanicError struct {
+	// Hook is the name of the function that panicked.
+	Hook string
+
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error returns the message with the name of the hook.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Hook, e.Value)
+}
+
+// Unwrap returns e.Value if it is an error, nil otherwise.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// callHook returns fn(c), recovers a panic in fn as *PanicError.
+func callHook(fn func(*cli.Context) error, c *cli.Context) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Hook: hookName(fn), Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return fn(c)
+}
+
+// callPred returns pred(c), recovers a panic in pred as *PanicError.
+func callPred(pred func(*cli.Context) bool, c *cli.Context) (ok bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Hook: hookName(pred), Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return pred(c), nil
+}
+
+// hookName returns the name of the function fn.
+func hookName(fn interface{}) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
@@ -1,6 +1,8 @@
 package clix_test
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -43,3 +45,115 @@ func TestChain(t *testing.T) {
 		t.Fatalf("-want +got\n%s", diff)
 	}
 }
+
+func TestChainAll(t *testing.T) {
+	var got []int
+	a0 := func(*cli.Context) error {
+		got = append(got, 0)
+		return errors.New("e0")
+	}
+	a1 := func(*cli.Context) error {
+		got = append(got, 1)
+		return clix.Errors{errors.New("e1"), errors.New("e2")}
+	}
+	a2 := func(*cli.Context) error {
+		got = append(got, 2)
+		return nil
+	}
+
+	err := clix.ChainAll(a0, a1, a2)(nil)
+	if diff := cmp.Diff([]int{0, 1, 2}, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+	if diff := cmp.Diff("e0\ne1\ne2", err.Error()); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func TestWhen(t *testing.T) {
+	var got []bool
+	fn := func(*cli.Context) error {
+		got = append(got, true)
+		return nil
+	}
+	_ = clix.When(func(*cli.Context) bool { return false }, fn)(nil)
+	_ = clix.When(func(*cli.Context) bool { return true }, fn)(nil)
+	if diff := cmp.Diff([]bool{true}, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func panicHook(*cli.Context) error {
+	panic("boom")
+}
+
+func panicPred(*cli.Context) bool {
+	panic("boom")
+}
+
+func TestWhen_panic(t *testing.T) {
+	yes := func(*cli.Context) bool { return true }
+	cases := []struct {
+		Name string
+		Fn   func(*cli.Context) error
+		Want string
+	}{
+		{"pred", clix.When(panicPred), "panic in github.com/takumakei/go-urfave-cli/clix_test.panicPred: boom"},
+		{"fn", clix.When(yes, panicHook), "panic in github.com/takumakei/go-urfave-cli/clix_test.panicHook: boom"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := c.Fn(nil)
+			var panicErr *clix.PanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("err=%v, want *clix.PanicError", err)
+			}
+			if diff := cmp.Diff(c.Want, err.Error()); diff != "" {
+				t.Fatalf("-want +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var mu sync.Mutex
+	var n int
+	ok := func(*cli.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return nil
+	}
+	ng := func(*cli.Context) error {
+		return errors.New("ng")
+	}
+
+	err := clix.Parallel(ok, panicHook, ok, ng)(nil)
+	if n != 2 {
+		t.Errorf("n=%d, want 2", n)
+	}
+	want := "panic in github.com/takumakei/go-urfave-cli/clix_test.panicHook: boom\nng"
+	if diff := cmp.Diff(want, err.Error()); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+	var panicErr *clix.PanicError
+	if !errors.As(err, &panicErr) || len(panicErr.Stack) == 0 {
+		t.Errorf("errors.As(err, &panicErr)=%v", panicErr)
+	}
+}
+
+func TestFinally(t *testing.T) {
+	called := false
+	final := func(*cli.Context) error {
+		called = true
+		return nil
+	}
+	err := clix.Finally(panicHook, final)(nil)
+	if !called {
+		t.Error("final is not called")
+	}
+	var panicErr *clix.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("err=%v, want *clix.PanicError", err)
+	}
+}
@@ -0,0 +1,218 @@
+// Package clixtest provides the helpers to test cli.App built with clix.
+//
+// Run is not isolated from the process. It sets the environment variables of
+// the process and replaces os.Stdin while the app runs, and serializes the
+// calls by a global mutex. The tests calling Run in parallel do not run the
+// apps at the same time, and the other code of the tests must not read or
+// change the environment while Run is in progress.
+package clixtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/takumakei/go-exit"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+// Options is the input of Run.
+type Options struct {
+	// Args is the command line arguments without the program name.
+	Args []string
+
+	// Env is the environment variables set during Run.
+	Env map[string]string
+
+	// EnvPrefix is the prefix of the environment variables removed during Run
+	// unless they are in Env, so that the app is isolated from the
+	// environment of the test. No variable is removed if EnvPrefix is empty.
+	EnvPrefix string
+
+	// Unsetenv is the environment variables removed during Run unless they
+	// are in Env.
+	Unsetenv []string
+
+	// Files is the contents of the files by the names of the environment
+	// variables, such as "APP_PASSWORD_FILE".
+	// Each content is written to a temporary file, and the environment
+	// variable is set to its path during Run.
+	Files map[string]string
+
+	// Stdin is the content of the standard input.
+	Stdin string
+
+	// FlagSet is the FlagSet whose state is returned as Result.FlagSet.
+	FlagSet clix.FlagSet
+}
+
+// Result is the output of Run.
+type Result struct {
+	// Stdout is what the app wrote to app.Writer.
+	Stdout string
+
+	// Stderr is what the app wrote to app.ErrWriter.
+	Stderr string
+
+	// Err is the error returned by app.Run.
+	Err error
+
+	// ExitCode is the exit status that the app would exit with,
+	// cli.ExitCoder and exit.StatusCode are respected.
+	ExitCode int
+
+	// FlagSet is the snapshot of Options.FlagSet after app.Run.
+	FlagSet clix.FlagSetSnapshot
+}
+
+// mu serializes Run, since the environment variables and os.Stdin are
+// shared by the process.
+var mu sync.Mutex
+
+// Run calls newApp and runs the app with opts, returns the result.
+//
+// newApp is called after the environment variables are set, so that the
+// flags made by clix.NewFlagName see them, FilePath in particular.
+// The whole environment and os.Stdin are restored after Run, including the
+// variables changed by the app.
+//
+// app.ExitErrHandler of the app is called if it is set, otherwise the
+// message of cli.ExitCoder is written to Result.Stderr instead of exiting
+// the process.
+//
+// Run may be called from the parallel tests, though the calls are
+// serialized. The other code of the tests must not depend on the
+// environment variables changed by Run.
+func Run(t testing.TB, newApp func() *cli.App, opts Options) *Result {
+	t.Helper()
+
+	files := make(map[string]string, len(opts.Files))
+	if len(opts.Files) > 0 {
+		dir := t.TempDir()
+		i := 0
+		for name, content := range opts.Files {
+			path := filepath.Join(dir, fmt.Sprintf("%d-%s", i, name))
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			files[name] = path
+			i++
+		}
+	}
+
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	if _, err := stdin.WriteString(opts.Stdin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	restore := setenv(opts.EnvPrefix, opts.Unsetenv, opts.Env, files)
+	defer restore()
+
+	orig := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = orig }()
+
+	var stdout, stderr strings.Builder
+	app := newApp()
+	app.Reader = stdin
+	app.Writer = &stdout
+	app.ErrWriter = &stderr
+	// the default handler exits the process.
+	if app.ExitErrHandler == nil {
+		app.ExitErrHandler = func(_ *cli.Context, err error) {
+			var coder cli.ExitCoder
+			if errors.As(err, &coder) {
+				if msg := coder.Error(); len(msg) > 0 {
+					fmt.Fprintln(&stderr, msg)
+				}
+			}
+		}
+	}
+
+	name := app.Name
+	if len(name) == 0 {
+		name = "app"
+	}
+	// cli.NewApp uses the name of the test binary.
+	if len(app.HelpName) == 0 || app.HelpName == filepath.Base(os.Args[0]) {
+		app.HelpName = name
+	}
+	err = app.Run(append([]string{name}, opts.Args...))
+
+	return &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Err:      err,
+		ExitCode: exitCode(err),
+		FlagSet:  opts.FlagSet.Snapshot(),
+	}
+}
+
+// exitCode returns the exit status of err.
+func exitCode(err error) int {
+	var coder cli.ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return exit.StatusCode(err)
+}
+
+// setenv sets the environment variables, returns the function to restore
+// the whole environment as it was.
+func setenv(prefix string, unset []string, env, files map[string]string) func() {
+	saved := os.Environ()
+
+	for _, name := range unset {
+		os.Unsetenv(name)
+	}
+	if len(prefix) > 0 {
+		for _, kv := range saved {
+			if name, _, ok := splitEnv(kv); ok && strings.HasPrefix(name, prefix) {
+				os.Unsetenv(name)
+			}
+		}
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+	for name, path := range files {
+		os.Setenv(name, path)
+	}
+
+	return func() {
+		os.Clearenv()
+		for _, kv := range saved {
+			if name, value, ok := splitEnv(kv); ok {
+				os.Setenv(name, value)
+			}
+		}
+	}
+}
+
+// splitEnv splits kv of os.Environ into the name and the value.
+// The name may start with "=" on Windows, e.g. "=C:=C:\\".
+func splitEnv(kv string) (name, value string, ok bool) {
+	if len(kv) == 0 {
+		return "", "", false
+	}
+	i := strings.IndexByte(kv[1:], '=')
+	if i < 0 {
+		return "", "", false
+	}
+	return kv[:i+1], kv[i+2:], true
+}
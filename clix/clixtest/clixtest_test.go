@@ -0,0 +1,195 @@
+package clixtest_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-exit"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/takumakei/go-urfave-cli/clix/clixtest"
+	"github.com/urfave/cli/v2"
+)
+
+func testApp(fs clix.FlagSet) func() *cli.App {
+	return func() *cli.App {
+		prefix := clix.FlagPrefix("CLIXTEST_")
+		user := clix.NewFlagName(prefix, "", "user")
+		pass := clix.NewFlagName(prefix, "", "password")
+		code := clix.NewFlagName(prefix, "", "code")
+		password := new(clix.Secret)
+
+		app := cli.NewApp()
+		app.Name = "example"
+		app.Flags = []cli.Flag{
+			&cli.StringFlag{Name: user.Name, Aliases: user.Aliases, EnvVars: user.EnvVars, FilePath: user.FilePath},
+			clix.NewSecretFlag(pass, "", password),
+			&cli.IntFlag{Name: code.Name, Aliases: code.Aliases, EnvVars: code.EnvVars},
+		}
+		app.Before = fs.Init
+		app.Action = func(c *cli.Context) error {
+			fmt.Fprintf(c.App.Writer, "%s:%s", c.String(user.Name), password.Value())
+			switch c.Int(code.Name) {
+			case 0:
+				return nil
+			case 3:
+				return cli.Exit("cli exit", 3)
+			default:
+				return exit.Error(c.Int(code.Name), errors.New("go-exit"))
+			}
+		}
+		return app
+	}
+}
+
+func TestRun(t *testing.T) {
+	os.Setenv("CLIXTEST_USER", "ambient")
+	t.Cleanup(func() { os.Unsetenv("CLIXTEST_USER") })
+
+	type result struct {
+		Stdout   string
+		Stderr   string
+		ExitCode int
+		FlagSet  clix.FlagSetSnapshot
+	}
+	cases := []struct {
+		Name string
+		Opts clixtest.Options
+		Want result
+	}{
+		{
+			"ambient",
+			clixtest.Options{},
+			result{Stdout: "ambient:", FlagSet: clix.FlagSetSnapshot{}},
+		},
+		{
+			"args",
+			clixtest.Options{Args: []string{"--user", "alice"}},
+			result{
+				Stdout: "alice:",
+				FlagSet: clix.FlagSetSnapshot{
+					"user": {Kind: clix.SourceArg, Name: "user"},
+					"u":    {Kind: clix.SourceArg, Name: "u"},
+				},
+			},
+		},
+		{
+			"env",
+			clixtest.Options{
+				Env:       map[string]string{"CLIXTEST_U": "bob"},
+				EnvPrefix: "CLIXTEST_",
+			},
+			result{Stdout: "bob:", FlagSet: clix.FlagSetSnapshot{}},
+		},
+		{
+			"files",
+			clixtest.Options{
+				Files:     map[string]string{"CLIXTEST_PASSWORD_FILE": "secret\n"},
+				EnvPrefix: "CLIXTEST_",
+			},
+			result{Stdout: ":secret", FlagSet: clix.FlagSetSnapshot{}},
+		},
+		{
+			"stdin",
+			clixtest.Options{
+				Args:      []string{"--password", "-"},
+				Stdin:     "from-stdin\n",
+				EnvPrefix: "CLIXTEST_",
+			},
+			result{
+				Stdout: ":from-stdin",
+				FlagSet: clix.FlagSetSnapshot{
+					"password": {Kind: clix.SourceArg, Name: "password"},
+					"p":        {Kind: clix.SourceArg, Name: "p"},
+				},
+			},
+		},
+		{
+			"cli.Exit",
+			clixtest.Options{Args: []string{"-c", "3"}, EnvPrefix: "CLIXTEST_"},
+			result{
+				Stdout:   ":",
+				Stderr:   "cli exit\n",
+				ExitCode: 3,
+				FlagSet: clix.FlagSetSnapshot{
+					"code": {Kind: clix.SourceArg, Name: "code"},
+					"c":    {Kind: clix.SourceArg, Name: "c"},
+				},
+			},
+		},
+		{
+			"exit.Error",
+			clixtest.Options{Env: map[string]string{"CLIXTEST_CODE": "42"}, EnvPrefix: "CLIXTEST_"},
+			result{Stdout: ":", ExitCode: 42, FlagSet: clix.FlagSetSnapshot{}},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			fs := clix.NewFlagSet()
+			c.Opts.FlagSet = fs
+			r := clixtest.Run(t, testApp(fs), c.Opts)
+			got := result{Stdout: r.Stdout, Stderr: r.Stderr, ExitCode: r.ExitCode, FlagSet: r.FlagSet}
+			if diff := cmp.Diff(c.Want, got); diff != "" {
+				t.Fatalf("-want +got\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("restored", func(t *testing.T) {
+		if got := os.Getenv("CLIXTEST_USER"); got != "ambient" {
+			t.Errorf("CLIXTEST_USER=%q", got)
+		}
+		if _, ok := os.LookupEnv("CLIXTEST_CODE"); ok {
+			t.Error("CLIXTEST_CODE is not restored")
+		}
+	})
+}
+
+func TestRun_environment(t *testing.T) {
+	t.Setenv("CLIXTEST_AMBIENT", "ambient")
+	newApp := func() *cli.App {
+		app := cli.NewApp()
+		app.Action = func(*cli.Context) error {
+			os.Setenv("CLIXTEST_SET_BY_APP", "1")
+			os.Unsetenv("CLIXTEST_AMBIENT")
+			return nil
+		}
+		return app
+	}
+	clixtest.Run(t, newApp, clixtest.Options{Env: map[string]string{"CLIXTEST_USER": "alice"}})
+
+	if _, ok := os.LookupEnv("CLIXTEST_SET_BY_APP"); ok {
+		t.Error("CLIXTEST_SET_BY_APP is not removed")
+	}
+	if _, ok := os.LookupEnv("CLIXTEST_USER"); ok {
+		t.Error("CLIXTEST_USER is not removed")
+	}
+	if got := os.Getenv("CLIXTEST_AMBIENT"); got != "ambient" {
+		t.Errorf("CLIXTEST_AMBIENT=%q", got)
+	}
+}
+
+func TestRun_exitErrHandler(t *testing.T) {
+	var handled error
+	newApp := func() *cli.App {
+		app := cli.NewApp()
+		app.Action = func(*cli.Context) error {
+			return cli.Exit("failed", 2)
+		}
+		app.ExitErrHandler = func(_ *cli.Context, err error) {
+			handled = err
+		}
+		return app
+	}
+	r := clixtest.Run(t, newApp, clixtest.Options{})
+	if handled == nil || handled.Error() != "failed" {
+		t.Errorf("handled=%v", handled)
+	}
+	if r.ExitCode != 2 || r.Stderr != "" {
+		t.Errorf("ExitCode=%d, Stderr=%q", r.ExitCode, r.Stderr)
+	}
+}
@@ -0,0 +1,109 @@
+package clixtest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+// update is the flag `-clixtest.update` of the test to update the golden
+// files. The name is qualified, so that it does not collide with `-update`
+// defined by the tests or the other packages.
+var update = flag.Bool("clixtest.update", false, "update the golden files of clixtest")
+
+// GoldenHelp renders the help of newApp() and all of its commands, and
+// compares it with the golden file of path.
+// The golden file is updated if the test runs with `-clixtest.update`.
+//
+// The environment variables of the flags and their `<ENV>_FILE` are removed
+// while rendering, since cli shows their values as the defaults.
+func GoldenHelp(t testing.TB, newApp func() *cli.App, path string) {
+	t.Helper()
+
+	var b strings.Builder
+	app := newApp()
+	var unset []string
+	for _, e := range clix.DocEntries(app) {
+		unset = append(append(unset, e.EnvVars...), e.FileEnvVars...)
+	}
+	render := func(args ...string) {
+		r := Run(t, newApp, Options{Args: append(args, "--help"), Unsetenv: unset})
+		name := strings.Join(append([]string{app.Name}, args...), " ")
+		if r.Err != nil {
+			t.Fatalf("%s: %v", name, r.Err)
+		}
+		fmt.Fprintf(&b, "=== %s ===\n%s\n", name, r.Stdout)
+	}
+
+	render()
+	walkCommands(app.Commands, nil, func(path []string) {
+		render(path...)
+	})
+	Golden(t, path, b.String())
+}
+
+// GoldenEnv compares the mapping of the flags of app to their environment
+// variables with the golden file of path, so that renaming the environment
+// variables is caught in review.
+// The golden file is updated if the test runs with `-clixtest.update`.
+func GoldenEnv(t testing.TB, app *cli.App, path string) {
+	t.Helper()
+
+	var b strings.Builder
+	for _, e := range clix.DocEntries(app) {
+		flag := "--" + e.Flag
+		if len(e.Flag) == 1 {
+			flag = "-" + e.Flag
+		}
+		if len(e.Command) > 0 {
+			flag = e.Command + " " + flag
+		}
+		fmt.Fprintf(&b, "%s: %s", flag, strings.Join(e.EnvVars, ", "))
+		if len(e.FileEnvVars) > 0 {
+			fmt.Fprintf(&b, " (file: %s)", strings.Join(e.FileEnvVars, ", "))
+		}
+		b.WriteString("\n")
+	}
+	Golden(t, path, b.String())
+}
+
+// Golden compares got with the content of the golden file of path,
+// or writes got to the file if the test runs with `-clixtest.update`.
+func Golden(t testing.TB, path, got string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run the test with -clixtest.update to create it)", err)
+	}
+	if diff := cmp.Diff(string(want), got); diff != "" {
+		t.Errorf("%s: -want +got (run the test with -clixtest.update to accept)\n%s", path, diff)
+	}
+}
+
+// walkCommands calls fn with the names of each command in commands and
+// their subcommands in depth-first order.
+func walkCommands(commands []*cli.Command, path []string, fn func([]string)) {
+	for _, cmd := range commands {
+		p := append(append([]string(nil), path...), cmd.Name)
+		fn(p)
+		walkCommands(cmd.Subcommands, p, fn)
+	}
+}
@@ -0,0 +1,47 @@
+package clixtest_test
+
+import (
+	"testing"
+
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/takumakei/go-urfave-cli/clix/clixtest"
+	"github.com/urfave/cli/v2"
+)
+
+func testGoldenApp() *cli.App {
+	prefix := clix.FlagPrefix("CLIXTEST_GOLDEN_")
+	g := clix.NewGroup(prefix, "")
+	g.String(&cli.StringFlag{Name: "name", Usage: "the `name`", Value: "alice"})
+	s := clix.NewGroup(prefix, "server")
+	s.Int(&cli.IntFlag{Name: "port", Usage: "listening port", Value: 80})
+
+	app := cli.NewApp()
+	app.Name = "example"
+	app.Usage = "an example"
+	app.Flags = g.Flags()
+	app.Commands = []*cli.Command{
+		{
+			Name:  "serve",
+			Usage: "start the server",
+			Flags: s.Flags(),
+			Subcommands: []*cli.Command{
+				{Name: "reload", Usage: "reload the server"},
+			},
+		},
+	}
+	return app
+}
+
+func TestGoldenHelp(t *testing.T) {
+	clixtest.GoldenHelp(t, testGoldenApp, "testdata/help.golden")
+}
+
+func TestGoldenEnv(t *testing.T) {
+	clixtest.GoldenEnv(t, testGoldenApp(), "testdata/env.golden")
+}
+
+func TestGoldenHelp_env(t *testing.T) {
+	t.Setenv("CLIXTEST_GOLDEN_NAME", "bob")
+	t.Setenv("CLIXTEST_GOLDEN_SERVER_PORT", "8080")
+	clixtest.GoldenHelp(t, testGoldenApp, "testdata/help.golden")
+}
@@ -0,0 +1,116 @@
+package clix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ErrCollision represents an error where two or more flags share the same
+// name, alias or environment variable.
+var ErrCollision = errors.New("flags collide")
+
+// CheckCollisions returns all the collisions of the flags of app and all of
+// its subcommands together as Errors, or nil if there is no collision.
+//
+// The following are reported.
+//
+//   - the names and the aliases shared by the flags of the same command
+//   - the environment variables shared by the different flags
+//   - the environment variables `<ENV>_FILE` of a flag that is used as an
+//     environment variable by another flag, unless the naming passed to
+//     UseNaming on app has EnvPolicy.NoFile
+func CheckCollisions(app *cli.App) error {
+	var errs Errors
+	env := newCollisionIndex()
+
+	check := func(command string, flags []cli.Flag) {
+		names := newCollisionIndex()
+		for _, flag := range flags {
+			if isHelpOrVersionFlag(flag) {
+				continue
+			}
+			for _, name := range flag.Names() {
+				names.add(name, flag)
+			}
+			for _, v := range flagStringSliceField(flag, "EnvVars") {
+				env.add(v, flag)
+			}
+		}
+		where := ""
+		if len(command) > 0 {
+			where = fmt.Sprintf(" in command %q", command)
+		}
+		names.each(func(name string, flags []cli.Flag) {
+			errs = append(errs, fmt.Errorf("%w: %s%s is used by %s%s",
+				ErrCollision, prefixFor(name), name, describeFlags(flags), where))
+		})
+	}
+
+	check("", app.Flags)
+	walkCommands(app.Commands, nil, func(path []string, cmd *cli.Command) {
+		check(strings.Join(path, " "), cmd.Flags)
+	})
+
+	env.each(func(name string, flags []cli.Flag) {
+		errs = append(errs, fmt.Errorf("%w: $%s is used by %s",
+			ErrCollision, name, describeFlags(flags)))
+	})
+	for _, name := range env.keys {
+		if namingOf(app, name).Env.NoFile {
+			continue
+		}
+		file := name + "_FILE"
+		if others, ok := env.flags[file]; ok {
+			for _, flag := range env.flags[name] {
+				errs = append(errs, fmt.Errorf("%w: $%s of %s is used by %s",
+					ErrCollision, file, describeFlag(flag), describeFlags(others)))
+			}
+		}
+	}
+
+	return errs.Err()
+}
+
+// MustCheckCollisions panics if CheckCollisions(app) returns non-nil error.
+// MustCheckCollisions is intended to be called at the construction of app.
+func MustCheckCollisions(app *cli.App) {
+	if err := CheckCollisions(app); err != nil {
+		panic(err)
+	}
+}
+
+// collisionIndex is the flags indexed by the names in order of appearance.
+type collisionIndex struct {
+	keys  []string
+	flags map[string][]cli.Flag
+}
+
+func newCollisionIndex() *collisionIndex {
+	return &collisionIndex{flags: make(map[string][]cli.Flag)}
+}
+
+// add adds flag to the list of name unless flag is already in the list.
+func (x *collisionIndex) add(name string, flag cli.Flag) {
+	list, ok := x.flags[name]
+	if !ok {
+		x.keys = append(x.keys, name)
+	}
+	for _, f := range list {
+		if f == flag {
+			return
+		}
+	}
+	x.flags[name] = append(list, flag)
+}
+
+// each calls fn for each name used by two or more flags.
+func (x *collisionIndex) each(fn func(string, []cli.Flag)) {
+	for _, name := range x.keys {
+		if list := x.flags[name]; len(list) > 1 {
+			fn(name, list)
+		}
+	}
+}
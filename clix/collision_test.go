@@ -0,0 +1,98 @@
+package clix_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func testCollisionFlag(naming clix.Naming, group, name string) *cli.StringFlag {
+	fn := naming.NewFlagName(group, name)
+	return &cli.StringFlag{Name: fn.Name, Aliases: fn.Aliases, EnvVars: fn.EnvVars}
+}
+
+func TestCheckCollisions(t *testing.T) {
+	naming := clix.Naming{Prefix: clix.FlagPrefix("APP_")}
+
+	t.Run("ok", func(t *testing.T) {
+		shared := testCollisionFlag(naming, "", "verbose")
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{
+			shared,
+			testCollisionFlag(naming, "", "tls-cert"),
+		}
+		app.Commands = []*cli.Command{
+			{
+				Name: "serve",
+				Flags: []cli.Flag{
+					shared,
+					testCollisionFlag(naming, "serve", "tls-cert"),
+				},
+			},
+		}
+		if err := clix.CheckCollisions(app); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{
+			testCollisionFlag(naming, "", "tls-cert"),
+			testCollisionFlag(naming, "", "tls-ca"),
+		}
+		app.Commands = []*cli.Command{
+			{
+				Name: "serve",
+				Flags: []cli.Flag{
+					testCollisionFlag(naming, "", "key"),
+					testCollisionFlag(naming, "", "key-file"),
+					testCollisionFlag(naming, "serve", "port"),
+					testCollisionFlag(naming, "serve", "path"),
+				},
+			},
+		}
+		err := clix.CheckCollisions(app)
+		if !errors.Is(err, clix.ErrCollision) {
+			t.Fatalf("got %v, want ErrCollision", err)
+		}
+		want := `flags collide: --tc is used by --tls-cert [$APP_TLS_CERT, $APP_TC], --tls-ca [$APP_TLS_CA, $APP_TC]
+flags collide: --serve-p is used by --serve-port [$APP_SERVE_PORT, $APP_SERVE_P], --serve-path [$APP_SERVE_PATH, $APP_SERVE_P] in command "serve"
+flags collide: $APP_TC is used by --tls-cert [$APP_TLS_CERT, $APP_TC], --tls-ca [$APP_TLS_CA, $APP_TC]
+flags collide: $APP_SERVE_P is used by --serve-port [$APP_SERVE_PORT, $APP_SERVE_P], --serve-path [$APP_SERVE_PATH, $APP_SERVE_P]
+flags collide: $APP_KEY_FILE of --key [$APP_KEY, $APP_K] is used by --key-file [$APP_KEY_FILE, $APP_KF]`
+		if diff := cmp.Diff(want, err.Error()); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+	})
+
+	t.Run("AliasNone", func(t *testing.T) {
+		naming := clix.Naming{Prefix: clix.FlagPrefix("CLIX_TEST_COLLISION_"), Alias: clix.AliasNone}
+
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{
+			testCollisionFlag(naming, "", "tls-cert"),
+			testCollisionFlag(naming, "", "tls-ca"),
+		}
+		clix.MustCheckCollisions(app)
+	})
+
+	t.Run("NoFile", func(t *testing.T) {
+		naming := clix.Naming{Prefix: clix.FlagPrefix("APP_"), Env: clix.EnvPolicy{NoFile: true}}
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{
+			testCollisionFlag(naming, "", "key"),
+			testCollisionFlag(naming, "", "key-file"),
+		}
+		if err := clix.CheckCollisions(app); !errors.Is(err, clix.ErrCollision) {
+			t.Fatalf("got %v, want ErrCollision without UseNaming", err)
+		}
+		clix.UseNaming(app, naming)
+		if err := clix.CheckCollisions(app); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
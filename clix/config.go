@@ -0,0 +1,245 @@
+package clix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFile represents the flag of the path of a config file,
+// that fills the flags set by neither arguments nor environment variables.
+//
+// The keys of the config file are the names of the flags.
+// Nested tables are joined with "-", and "." in the keys is read as "-",
+// so that the following keys are all for the flag "server-tls-cert".
+//
+//	server-tls-cert: cert.pem
+//	server.tls-cert: cert.pem
+//	server:
+//	  tls-cert: cert.pem
+//
+// An array is read as multiple values of the flag such as cli.StringSliceFlag.
+type ConfigFile struct {
+	// FlagConfig is the path of the config file.
+	FlagConfig *cli.StringFlag
+
+	// FlagSet is clix.FlagSet.
+	FlagSet FlagSet
+}
+
+// NewConfigFile returns *ConfigFile.
+func NewConfigFile(prefix FlagPrefix, group string) *ConfigFile {
+	name := NewFlagNameAlias(prefix, group, "config", "conf")
+	return &ConfigFile{
+		FlagConfig: &cli.StringFlag{
+			Name:        name.Name,
+			Aliases:     name.Aliases,
+			Usage:       "config `file` [json|yaml|toml]",
+			EnvVars:     name.EnvVars,
+			FilePath:    name.FilePath,
+			TakesFile:   true,
+			Destination: new(string),
+		},
+		FlagSet: NewFlagSet(),
+	}
+}
+
+// Flags returns []cli.Flag{f.FlagConfig}.
+func (f *ConfigFile) Flags() []cli.Flag {
+	return []cli.Flag{f.FlagConfig}
+}
+
+// Path returns the value of f.FlagConfig.
+func (f *ConfigFile) Path() string {
+	return *f.FlagConfig.Destination
+}
+
+// Before calls f.FlagSet.Init(c), then loads the config file if f.Path() is
+// not empty unless the parent command has loaded it, sets the values of the
+// flags of c that are not set yet.
+// Before is intended to be used as cli.BeforeFunc.
+//
+// Before sets only the flags of the command of c. Use Before in the Before
+// of the subcommands as well to set their flags, which reuses the values
+// loaded by the parent command.
+//
+// Before should be called before the Init of the other FlagSets in c,
+// in order to let them know the flags set by the config file.
+func (f *ConfigFile) Before(c *cli.Context) error {
+	if err := f.FlagSet.Init(c); err != nil {
+		return err
+	}
+	loaded, ok := loadContext(c, configKey{f}).(*configValues)
+	if !ok {
+		path := f.Path()
+		if len(path) == 0 {
+			return nil
+		}
+		values, err := LoadConfigFile(path)
+		if err != nil {
+			return err
+		}
+		loaded = &configValues{path: path, values: values}
+		storeContext(c, configKey{f}, loaded)
+	}
+	path, values := loaded.path, loaded.values
+	src := Source{Kind: SourceConfig, Name: path}
+	run := f.FlagSet.In(c)
+	for _, flag := range localFlags(c) {
+		if flag == cli.Flag(f.FlagConfig) || run.IsSet(flag) {
+			continue
+		}
+		list, ok := lookupConfig(values, flag)
+		if !ok {
+			continue
+		}
+		name := flag.Names()[0]
+		for _, v := range list {
+			if err := c.Set(name, v); err != nil {
+				return fmt.Errorf("%s: invalid value %q for flag %q: %w", path, v, name, err)
+			}
+		}
+		recordSource(c, flag, src)
+		for _, name := range flag.Names() {
+			f.FlagSet.set(name, src)
+			run.set(name, src)
+		}
+	}
+	return nil
+}
+
+// configKey is the key of the context.Context of cli.Context, where the
+// values loaded by ConfigFile in the run are kept as *configValues.
+type configKey struct{ config *ConfigFile }
+
+// configValues is the values loaded from the config file of path.
+type configValues struct {
+	path   string
+	values map[string][]string
+}
+
+// lookupConfig returns the values for flag in values.
+func lookupConfig(values map[string][]string, flag cli.Flag) ([]string, bool) {
+	for _, name := range flag.Names() {
+		if v, ok := values[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// localFlags returns the flags of the command of c.
+func localFlags(c *cli.Context) []cli.Flag {
+	if c.Command != nil && len(c.Command.Name) > 0 {
+		return c.Command.Flags
+	}
+	return c.App.Flags
+}
+
+// LoadConfigFile reads the config file, returns the values by the flag names.
+// The format is chosen by the extension of path, one of
+// ".json", ".yaml", ".yml" and ".toml".
+func LoadConfigFile(path string) (map[string][]string, error) {
+	p, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(p, &doc)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(p, &doc)
+	case ".toml":
+		var m map[string]interface{}
+		err = toml.Unmarshal(p, &m)
+		doc = m
+	default:
+		return nil, fmt.Errorf("%s: unknown config file format %q", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	values := make(map[string][]string)
+	if err := flattenConfig(values, "", doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenConfig stores the values in v into values with the key joined with
+// the prefix.
+func flattenConfig(values map[string][]string, prefix string, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		for k, e := range v {
+			if err := flattenConfig(values, joinConfigKey(prefix, k), e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[interface{}]interface{}:
+		for k, e := range v {
+			if err := flattenConfig(values, joinConfigKey(prefix, fmt.Sprint(k)), e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, e := range v {
+			s, err := configScalar(prefix, e)
+			if err != nil {
+				return err
+			}
+			list = append(list, s)
+		}
+		values[prefix] = list
+		return nil
+	}
+	s, err := configScalar(prefix, v)
+	if err != nil {
+		return err
+	}
+	values[prefix] = []string{s}
+	return nil
+}
+
+// joinConfigKey returns the flag name of key in prefix.
+func joinConfigKey(prefix, key string) string {
+	key = strings.ReplaceAll(key, ".", "-")
+	if len(prefix) > 0 {
+		return prefix + "-" + key
+	}
+	return key
+}
+
+// configScalar returns the string representation of v.
+func configScalar(key string, v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("%q: unsupported value of type %T", key, v)
+}
@@ -0,0 +1,155 @@
+package clix_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": "server:\n  address: localhost:80\n  tls-ca: [a.pem, b.pem]\nlog.level: debug\ncount: 1000000\n",
+		"config.json": `{"server": {"address": "localhost:80", "tls-ca": ["a.pem", "b.pem"]}, "log.level": "debug", "count": 1000000}`,
+		"config.toml": "\"log.level\" = \"debug\"\ncount = 1000000\n[server]\naddress = \"localhost:80\"\ntls-ca = [\"a.pem\", \"b.pem\"]\n",
+	}
+	want := map[string][]string{
+		"server-address": {"localhost:80"},
+		"server-tls-ca":  {"a.pem", "b.pem"},
+		"log-level":      {"debug"},
+		"count":          {"1000000"},
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := clix.LoadConfigFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s -want +got\n%s", name, diff)
+		}
+	}
+
+	if _, err := clix.LoadConfigFile(filepath.Join(dir, "config.ini")); err == nil {
+		t.Error("no error for unknown format")
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server:\n  address: from-config\n  port: 80\n  tls-ca: [a.pem, b.pem]\n  name: from-config\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("CLIX_TEST_CONFIG_SERVER_PORT", "8080")
+	t.Cleanup(func() { os.Unsetenv("CLIX_TEST_CONFIG_SERVER_PORT") })
+
+	config := clix.NewConfigFile(clix.FlagPrefix("CLIX_TEST_CONFIG_"), "")
+	group := clix.NewGroup(clix.FlagPrefix("CLIX_TEST_CONFIG_"), "server")
+	address := group.String(&cli.StringFlag{Name: "address", Value: "default"})
+	port := group.Int(&cli.IntFlag{Name: "port"})
+	cas := group.StringSlice(&cli.StringSliceFlag{Name: "tls-ca", Value: cli.NewStringSlice("default.pem")})
+	name := group.String(&cli.StringFlag{Name: "name"})
+	other := group.String(&cli.StringFlag{Name: "other", Value: "default"})
+
+	type value struct {
+		Address string
+		Port    int
+		CAs     []string
+		Name    string
+		Other   string
+		Sources []string
+	}
+	want := value{
+		Address: "from-config",
+		Port:    8080,
+		CAs:     []string{"a.pem", "b.pem"},
+		Name:    "from-arg",
+		Other:   "default",
+		Sources: []string{
+			"config:" + path,
+			"env:CLIX_TEST_CONFIG_SERVER_PORT",
+			"config:" + path,
+			"arg:--server-name",
+			"default",
+		},
+	}
+
+	var got value
+	app := cli.NewApp()
+	app.Flags = clix.Flags(config.Flags(), group.Flags())
+	app.Before = clix.Chain(config.Before, group.Before)
+	app.Action = func(c *cli.Context) error {
+		got.Address = address.Value()
+		got.Port = port.Value()
+		got.CAs = cas.Value()
+		got.Name = name.Value()
+		got.Other = other.Value()
+		got.Sources = []string{
+			address.Source().String(),
+			port.Source().String(),
+			cas.Source().String(),
+			name.Source().String(),
+			other.Source().String(),
+		}
+		return nil
+	}
+	if err := app.Run([]string{"prog", "--config", path, "--server-name", "from-arg"}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+
+	// the sources are not kept after the run.
+	if len(app.Metadata) != 0 {
+		t.Errorf("app.Metadata=%v", app.Metadata)
+	}
+}
+
+func TestConfigFile_subcommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("name: from-config\nport: 80\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := clix.NewConfigFile(clix.FlagPrefix("CLIX_TEST_CONFIG_"), "")
+	flagName := &cli.StringFlag{Name: "name"}
+	flagPort := &cli.IntFlag{Name: "port"}
+
+	var name string
+	var port int
+	var src clix.Source
+	app := cli.NewApp()
+	app.Flags = clix.Flags(config.Flags(), flagName)
+	app.Before = config.Before
+	app.Commands = []*cli.Command{
+		{
+			Name:   "serve",
+			Flags:  []cli.Flag{flagPort},
+			Before: config.Before,
+			Action: func(c *cli.Context) error {
+				name, port = c.String(flagName.Name), c.Int(flagPort.Name)
+				src = config.FlagSet.In(c).Source(flagPort)
+				return nil
+			},
+		},
+	}
+	if err := app.Run([]string{"prog", "--config", path, "serve"}); err != nil {
+		t.Fatal(err)
+	}
+	if name != "from-config" || port != 80 {
+		t.Fatalf("name=%q port=%d", name, port)
+	}
+	if want := (clix.Source{Kind: clix.SourceConfig, Name: path}); src != want {
+		t.Fatalf("got %+v, want %+v", src, want)
+	}
+}
@@ -0,0 +1,179 @@
+package clix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ErrMissingFlags represents an error where the flags required by a
+// constraint are not set.
+var ErrMissingFlags = errors.New("required flags are not set")
+
+// ErrForbiddenFlags represents an error where the flags forbidden by a
+// constraint are set.
+var ErrForbiddenFlags = errors.New("forbidden flags are set")
+
+// Constraint represents a relationship between flags,
+// returns non-nil error if fs violates it.
+type Constraint func(fs FlagSet) error
+
+// Validate returns a function that calls fs.Init(c), then checks all of
+// constraint against fs.In(c) and returns all the violations together as
+// Errors.
+// Validate is intended to be used as cli.BeforeFunc.
+func (fs FlagSet) Validate(constraint ...Constraint) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if err := fs.Init(c); err != nil {
+			return err
+		}
+		return CheckConstraints(fs.In(c), constraint...)
+	}
+}
+
+// CheckConstraints checks all of constraint, returns the violations as
+// Errors, or nil if there is no violation.
+func CheckConstraints(fs FlagSet, constraint ...Constraint) error {
+	var errs Errors
+	for _, c := range constraint {
+		if err := c(fs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.Err()
+}
+
+// MutuallyExclusive returns the constraint that at most one of flag is set.
+//
+// As same as FlagSet.Exclusive, the command line arguments are searched at
+// first, and the others are searched only if none is set in the arguments.
+func MutuallyExclusive(flag ...cli.Flag) Constraint {
+	return func(fs FlagSet) error {
+		if found := pickFlags(flag, fs.IsSetArgs); len(found) > 1 {
+			return fmt.Errorf("%w in args (%s)", ErrExclusiveFlags, describeFlags(found))
+		} else if len(found) == 1 {
+			return nil
+		}
+		if found := pickFlags(flag, fs.IsSet); len(found) > 1 {
+			return fmt.Errorf("%w in envs (%s)", ErrExclusiveFlags, describeFlags(found))
+		}
+		return nil
+	}
+}
+
+// ExactlyOneOf returns the constraint that exactly one of flag is set.
+func ExactlyOneOf(flag ...cli.Flag) Constraint {
+	exclusive := MutuallyExclusive(flag...)
+	required := RequiresOneOf(flag...)
+	return func(fs FlagSet) error {
+		if err := required(fs); err != nil {
+			return err
+		}
+		return exclusive(fs)
+	}
+}
+
+// RequiresOneOf returns the constraint that at least one of flag is set.
+func RequiresOneOf(flag ...cli.Flag) Constraint {
+	return func(fs FlagSet) error {
+		if len(pickFlags(flag, fs.IsSet)) == 0 {
+			return fmt.Errorf("%w: one of (%s)", ErrMissingFlags, describeFlags(flag))
+		}
+		return nil
+	}
+}
+
+// RequiredTogether returns the constraint that all or none of flag are set.
+func RequiredTogether(flag ...cli.Flag) Constraint {
+	return func(fs FlagSet) error {
+		set := pickFlags(flag, fs.IsSet)
+		if len(set) == 0 || len(set) == len(flag) {
+			return nil
+		}
+		unset := pickFlags(flag, func(f cli.Flag) bool { return !fs.IsSet(f) })
+		return fmt.Errorf("%w: %s (required together with %s)",
+			ErrMissingFlags, describeFlags(unset), describeFlags(set))
+	}
+}
+
+// Requires returns the constraint that all of required are set if flag is set.
+func Requires(flag cli.Flag, required ...cli.Flag) Constraint {
+	return RequiredIf("set "+describeFlag(flag), func(fs FlagSet) bool {
+		return fs.IsSet(flag)
+	}, required...)
+}
+
+// RequiredIf returns the constraint that all of flag are set if cond returns
+// true. The reason describes cond in the error message.
+//
+//	e.g.
+//	RequiredIf("network is tcp", func(clix.FlagSet) bool {
+//		return *flagNetwork.Destination == "tcp"
+//	}, flagAddress)
+func RequiredIf(reason string, cond func(FlagSet) bool, flag ...cli.Flag) Constraint {
+	return func(fs FlagSet) error {
+		if !cond(fs) {
+			return nil
+		}
+		unset := pickFlags(flag, func(f cli.Flag) bool { return !fs.IsSet(f) })
+		if len(unset) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: %s (required if %s)", ErrMissingFlags, describeFlags(unset), reason)
+	}
+}
+
+// ForbiddenIf returns the constraint that none of flag is set if cond
+// returns true. The reason describes cond in the error message.
+//
+//	e.g.
+//	ForbiddenIf("--tls-gen-cert is true", func(clix.FlagSet) bool {
+//		return *flagGenCert.Destination
+//	}, flagCert, flagKey)
+func ForbiddenIf(reason string, cond func(FlagSet) bool, flag ...cli.Flag) Constraint {
+	return func(fs FlagSet) error {
+		if !cond(fs) {
+			return nil
+		}
+		set := pickFlags(flag, fs.IsSet)
+		if len(set) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: %s (forbidden if %s)", ErrForbiddenFlags, describeFlags(set), reason)
+	}
+}
+
+// pickFlags returns the flags that fn returns true.
+func pickFlags(flags []cli.Flag, fn func(cli.Flag) bool) []cli.Flag {
+	var found []cli.Flag
+	for _, f := range flags {
+		if fn(f) {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+// describeFlags returns the descriptions of flags joined with ", ".
+func describeFlags(flags []cli.Flag) string {
+	list := make([]string, len(flags))
+	for i, f := range flags {
+		list[i] = describeFlag(f)
+	}
+	return strings.Join(list, ", ")
+}
+
+// describeFlag returns the name of flag with its environment variables.
+//
+//	e.g.
+//	"--tls-cert [$APP_TLS_CERT, $APP_TLSCRT]"
+func describeFlag(flag cli.Flag) string {
+	name := flag.Names()[0]
+	s := prefixFor(name) + name
+	if envVars := flagStringSliceField(flag, "EnvVars"); len(envVars) > 0 {
+		s += " [$" + strings.Join(envVars, ", $") + "]"
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+package clix_test
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestFlagSet_Validate(t *testing.T) {
+	var (
+		flagA = &cli.StringFlag{Name: "a", EnvVars: []string{"CLIX_TEST_CONSTRAINT_A"}}
+		flagB = &cli.StringFlag{Name: "b", EnvVars: []string{"CLIX_TEST_CONSTRAINT_B"}}
+		flagC = &cli.StringFlag{Name: "cert"}
+		flagK = &cli.StringFlag{Name: "key"}
+		flagG = &cli.BoolFlag{Name: "gen", Destination: new(bool)}
+	)
+
+	constraints := []clix.Constraint{
+		clix.ExactlyOneOf(flagA, flagB),
+		clix.RequiredTogether(flagC, flagK),
+		clix.RequiredIf("--gen is false", func(clix.FlagSet) bool { return !*flagG.Destination }, flagC),
+	}
+
+	cases := []struct {
+		Env  string
+		Args []string
+		Want string
+	}{
+		{"", []string{"-a", "1", "--cert", "c", "--key", "k"}, ""},
+		{"", []string{"-b", "1", "--gen"}, ""},
+		{"", []string{"--gen"}, "required flags are not set: one of (-a [$CLIX_TEST_CONSTRAINT_A], -b [$CLIX_TEST_CONSTRAINT_B])"},
+		{"", []string{"-a", "1", "-b", "2", "--key", "k"}, strings.Join([]string{
+			"more than one flags are set in args (-a [$CLIX_TEST_CONSTRAINT_A], -b [$CLIX_TEST_CONSTRAINT_B])",
+			"required flags are not set: --cert (required together with --key)",
+			"required flags are not set: --cert (required if --gen is false)",
+		}, "\n")},
+		{"1", []string{"--gen"}, ""},
+		{"1", []string{"-b", "1", "--gen"}, ""},
+	}
+
+	for i, c := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if len(c.Env) > 0 {
+				os.Setenv("CLIX_TEST_CONSTRAINT_A", c.Env)
+				t.Cleanup(func() { os.Unsetenv("CLIX_TEST_CONSTRAINT_A") })
+			}
+
+			fs := clix.NewFlagSet()
+			app := cli.NewApp()
+			app.Flags = []cli.Flag{flagA, flagB, flagC, flagK, flagG}
+			app.Before = fs.Validate(constraints...)
+			app.Action = func(*cli.Context) error { return nil }
+			err := app.Run(append([]string{"prog"}, c.Args...))
+			got := ""
+			if err != nil {
+				got = err.Error()
+				if !errors.Is(err, clix.ErrMissingFlags) && !errors.Is(err, clix.ErrExclusiveFlags) {
+					t.Errorf("unexpected error %v", err)
+				}
+			}
+			if diff := cmp.Diff(c.Want, got); diff != "" {
+				t.Fatalf("-want +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRequires(t *testing.T) {
+	var (
+		flagTLS  = &cli.BoolFlag{Name: "tls"}
+		flagCert = &cli.StringFlag{Name: "tls-cert", EnvVars: []string{"APP_TLS_CERT"}}
+	)
+	fs := clix.NewFlagSet()
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{flagTLS, flagCert}
+	app.Before = fs.Validate(clix.Requires(flagTLS, flagCert))
+	app.Action = func(*cli.Context) error { return nil }
+	err := app.Run([]string{"prog", "--tls"})
+	want := "required flags are not set: --tls-cert [$APP_TLS_CERT] (required if set --tls)"
+	if err == nil || err.Error() != want {
+		t.Fatalf("got %v, want %q", err, want)
+	}
+}
+
+func TestForbiddenIf(t *testing.T) {
+	var (
+		flagGen  = &cli.BoolFlag{Name: "gen", Destination: new(bool)}
+		flagCert = &cli.StringFlag{Name: "cert", EnvVars: []string{"APP_CERT"}}
+		flagKey  = &cli.StringFlag{Name: "key"}
+	)
+	constraint := clix.ForbiddenIf("--gen is true", func(clix.FlagSet) bool {
+		return *flagGen.Destination
+	}, flagCert, flagKey)
+
+	cases := []struct {
+		Args []string
+		Want string
+	}{
+		{[]string{"--cert", "c", "--key", "k"}, ""},
+		{[]string{"--gen"}, ""},
+		{[]string{"--gen", "--cert", "c"}, "forbidden flags are set: --cert [$APP_CERT] (forbidden if --gen is true)"},
+	}
+
+	for i, c := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			fs := clix.NewFlagSet()
+			app := cli.NewApp()
+			app.Flags = []cli.Flag{flagGen, flagCert, flagKey}
+			app.Before = fs.Validate(constraint)
+			app.Action = func(*cli.Context) error { return nil }
+			err := app.Run(append([]string{"prog"}, c.Args...))
+			got := ""
+			if err != nil {
+				got = err.Error()
+				if !errors.Is(err, clix.ErrForbiddenFlags) {
+					t.Errorf("unexpected error %v", err)
+				}
+			}
+			if diff := cmp.Diff(c.Want, got); diff != "" {
+				t.Fatalf("-want +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package clix
+
+import "path/filepath"
+
+// DefaultFiles is the locations of the default files of the flags.
+// It is used as Naming.DefaultFiles.
+//
+// The default file of the flag `name` is the first readable one of
+// `<dir>/<App>/defaults/<name>` for each dir in Dirs.
+type DefaultFiles struct {
+	// App is the name of the application.
+	App string
+
+	// Dirs is the directories searched in order.
+	// Each dir is expanded by ExpandPath.
+	Dirs []string
+}
+
+// NewDefaultFiles returns *DefaultFiles searching the user's config
+// directory, $XDG_CONFIG_HOME or `$HOME/.config`, and then `/etc`.
+func NewDefaultFiles(app string) *DefaultFiles {
+	var dirs []string
+	if dir := userConfigDir(); len(dir) > 0 {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, "/etc")
+	return &DefaultFiles{App: app, Dirs: dirs}
+}
+
+// Paths returns the candidates of the default file of name in order.
+func (d *DefaultFiles) Paths(name string) []string {
+	list := make([]string, len(d.Dirs))
+	for i, dir := range d.Dirs {
+		list[i] = filepath.Join(ExpandPath(dir), d.App, "defaults", name)
+	}
+	return list
+}
+
+// FilePath returns ReadableFilePath(d.Paths(name)...).
+func (d *DefaultFiles) FilePath(name string) string {
+	return ReadableFilePath(d.Paths(name)...)
+}
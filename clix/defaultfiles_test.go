@@ -0,0 +1,92 @@
+package clix_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func ExampleNaming_defaultFiles() {
+	// The flags of the prefix "EXAMPLE_" are read from one of the first file
+	// that is readable
+	//
+	//   1. pathname by the environment variable `EXAMPLE_PASSWORD_FILE`
+	//   2. or pathname of `$HOME/.config/example/defaults/password`
+	//   3. or pathname of `/etc/example/defaults/password`
+	naming := clix.Naming{
+		Prefix:       clix.FlagPrefix("EXAMPLE_"),
+		DefaultFiles: clix.NewDefaultFiles("example"),
+	}
+
+	password := naming.NewFlagName("", "password")
+	_ = password
+}
+
+func TestNewDefaultFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/alice/.xdg")
+	want := []string{
+		"/home/alice/.xdg/example/defaults/password",
+		"/etc/example/defaults/password",
+	}
+	got := clix.NewDefaultFiles("example").Paths("password")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func TestNaming_DefaultFiles(t *testing.T) {
+	user, system := t.TempDir(), t.TempDir()
+	write := func(dir, name string) string {
+		path := filepath.Join(dir, "example", "defaults", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	userPassword := write(user, "db-password")
+	write(system, "db-password")
+	systemUser := write(system, "db-user")
+
+	prefix := clix.FlagPrefix("CLIX_TEST_DEFAULT_")
+	naming := clix.Naming{
+		Prefix:       prefix,
+		DefaultFiles: &clix.DefaultFiles{App: "example", Dirs: []string{user, system}},
+	}
+
+	envFile := filepath.Join(t.TempDir(), "host")
+	if err := os.WriteFile(envFile, []byte("host"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLIX_TEST_DEFAULT_DB_HOST_FILE", envFile)
+	write(user, "db-host")
+
+	tests := []struct {
+		Name string
+		Want string
+	}{
+		{"password", userPassword},
+		{"user", systemUser},
+		{"host", envFile},
+		{"port", ""},
+	}
+	for _, tt := range tests {
+		got := naming.NewFlagName("db", tt.Name).FilePath
+		if got != tt.Want {
+			t.Errorf("%s: got %q, want %q", tt.Name, got, tt.Want)
+		}
+	}
+
+	if got := clix.NewFlagName(prefix, "db", "password").FilePath; got != "" {
+		t.Errorf("got %q, want empty without DefaultFiles", got)
+	}
+	if got := naming.NewGroup("db").String(&cli.StringFlag{Name: "password"}).Flag.FilePath; got != userPassword {
+		t.Errorf("Group: got %q, want %q", got, userPassword)
+	}
+}
@@ -0,0 +1,253 @@
+package clix
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// EnableDocsCommand appends DocsCommand to app.Commands.
+func EnableDocsCommand(app *cli.App) {
+	app.Commands = append(app.Commands, DocsCommand)
+}
+
+// DocsCommand is the command to print the reference of the environment
+// variables of the app.
+var DocsCommand = &cli.Command{
+	Name:      "docs",
+	Usage:     "print the reference of the environment variables",
+	ArgsUsage: " ",
+	Hidden:    true,
+	Action:    Docs,
+	Flags: []cli.Flag{
+		FlagDocsFormat,
+	},
+}
+
+// FlagDocsFormat is the flag of DocsCommand.
+var FlagDocsFormat = &cli.StringFlag{
+	Name:    "format",
+	Aliases: []string{"f"},
+	Usage:   "output `format` [markdown|man|env]",
+	Value:   "markdown",
+}
+
+// Docs writes the reference of the environment variables of the root app of
+// c to c.App.Writer in the format of FlagDocsFormat.
+func Docs(c *cli.Context) error {
+	app := rootApp(c)
+	entries := DocEntries(app)
+	switch format := c.String(FlagDocsFormat.Name); format {
+	case "markdown":
+		return WriteDocsMarkdown(c.App.Writer, app, entries)
+	case "man":
+		return WriteDocsMan(c.App.Writer, app, entries)
+	case "env":
+		return WriteDocsEnv(c.App.Writer, entries)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// DocEntry is the reference of the environment variables of a flag.
+type DocEntry struct {
+	// Command is the space separated names of the command the flag belongs to,
+	// empty string for the global flags.
+	Command string
+
+	// Flag is the name of the flag.
+	Flag string
+
+	// Aliases is the aliases of the flag.
+	Aliases []string
+
+	// EnvVars is the environment variables of the flag.
+	EnvVars []string
+
+	// FileEnvVars is the environment variables of the path of the file
+	// that FlagPrefix.FilePath looks up.
+	FileEnvVars []string
+
+	// Default is the default value of the flag, redacted if the flag is secret.
+	Default string
+
+	// Usage is the usage of the flag without the backquotes.
+	Usage string
+}
+
+// DocEntries returns the references of the flags of app and all of its
+// subcommands that have the environment variables.
+// The hidden commands are skipped.
+func DocEntries(app *cli.App) []DocEntry {
+	var entries []DocEntry
+	add := func(command string, flags []cli.Flag) {
+		for _, flag := range flags {
+			envVars := flagStringSliceField(flag, "EnvVars")
+			if len(envVars) == 0 {
+				continue
+			}
+			names := flag.Names()
+			value := defaultValue(flag)
+			if IsSecret(app, flag) && len(value) > 0 {
+				value = Redacted
+			}
+			entries = append(entries, DocEntry{
+				Command:     command,
+				Flag:        names[0],
+				Aliases:     names[1:],
+				EnvVars:     envVars,
+				FileEnvVars: fileEnvVarsOf(app, envVars),
+				Default:     value,
+				Usage:       strings.ReplaceAll(flagStringField(flag, "Usage"), "`", ""),
+			})
+		}
+	}
+
+	add("", app.Flags)
+	walkCommands(visibleCommands(app.Commands), nil, func(path []string, cmd *cli.Command) {
+		add(strings.Join(path, " "), cmd.Flags)
+	})
+	return entries
+}
+
+// visibleCommands returns the commands that are not hidden,
+// and their visible subcommands.
+func visibleCommands(commands []*cli.Command) []*cli.Command {
+	var list []*cli.Command
+	for _, cmd := range commands {
+		if cmd.Hidden {
+			continue
+		}
+		if len(cmd.Subcommands) > 0 {
+			c := *cmd
+			c.Subcommands = visibleCommands(cmd.Subcommands)
+			cmd = &c
+		}
+		list = append(list, cmd)
+	}
+	return list
+}
+
+// WriteDocsMarkdown writes entries to w in Markdown, a table per command.
+func WriteDocsMarkdown(w io.Writer, app *cli.App, entries []DocEntry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s environment variables\n", app.Name)
+	command := "\x00"
+	for _, e := range entries {
+		if e.Command != command {
+			command = e.Command
+			title := "Global flags"
+			if len(command) > 0 {
+				title = "Command `" + command + "`"
+			}
+			fmt.Fprintf(&b, "\n## %s\n\n", title)
+			b.WriteString("| Flag | Aliases | Environment variables | File variables | Default | Usage |\n")
+			b.WriteString("|------|---------|-----------------------|----------------|---------|-------|\n")
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCode(prefixFor(e.Flag)+e.Flag),
+			markdownCodes(e.Aliases, true),
+			markdownCodes(e.EnvVars, false),
+			markdownCodes(e.FileEnvVars, false),
+			markdownCode(e.Default),
+			markdownEscape(e.Usage),
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCodes returns list as the code spans joined with ", ".
+// The names of the flags are prefixed if flag is true.
+func markdownCodes(list []string, flag bool) string {
+	codes := make([]string, len(list))
+	for i, v := range list {
+		if flag {
+			v = prefixFor(v) + v
+		}
+		codes[i] = markdownCode(v)
+	}
+	return strings.Join(codes, ", ")
+}
+
+// markdownCode returns s as a code span in a table, or an empty string.
+func markdownCode(s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+// markdownEscape escapes the characters of s that break a table.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// WriteDocsMan writes entries to w as the ENVIRONMENT section of a man page.
+func WriteDocsMan(w io.Writer, app *cli.App, entries []DocEntry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 1\n", roffEscape(strings.ToUpper(app.Name)))
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", roffEscape(app.Name), roffEscape(app.Usage))
+	b.WriteString(".SH ENVIRONMENT\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, ".TP\n.B %s\n", roffEscape(strings.Join(e.EnvVars, ", ")))
+		flag := prefixFor(e.Flag) + e.Flag
+		if len(e.Command) > 0 {
+			flag = e.Command + " " + flag
+		}
+		fmt.Fprintf(&b, "Sets \\fB%s\\fR.", roffEscape(flag))
+		if len(e.Usage) > 0 {
+			fmt.Fprintf(&b, " %s.", roffEscape(strings.TrimSuffix(e.Usage, ".")))
+		}
+		if len(e.Default) > 0 {
+			fmt.Fprintf(&b, " Default: %s.", roffEscape(e.Default))
+		}
+		b.WriteString("\n")
+		if len(e.FileEnvVars) > 0 {
+			fmt.Fprintf(&b, ".br\nThe value is read from the file named by \\fB%s\\fR.\n",
+				roffEscape(strings.Join(e.FileEnvVars, ", ")))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// roffEscape escapes the characters of s special to roff.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`, "\n", " ").Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// WriteDocsEnv writes entries to w as a sample env-file,
+// all the variables are commented out with the default values.
+func WriteDocsEnv(w io.Writer, entries []DocEntry) error {
+	var b strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		flag := prefixFor(e.Flag) + e.Flag
+		if len(e.Command) > 0 {
+			flag = e.Command + " " + flag
+		}
+		fmt.Fprintf(&b, "# %s", flag)
+		if len(e.Usage) > 0 {
+			fmt.Fprintf(&b, ": %s", strings.ReplaceAll(e.Usage, "\n", " "))
+		}
+		b.WriteString("\n")
+		if len(e.EnvVars) > 1 {
+			fmt.Fprintf(&b, "# also %s\n", strings.Join(e.EnvVars[1:], ", "))
+		}
+		if len(e.FileEnvVars) > 0 {
+			fmt.Fprintf(&b, "# or the file named by %s\n", strings.Join(e.FileEnvVars, ", "))
+		}
+		fmt.Fprintf(&b, "#%s=%q\n", e.EnvVars[0], e.Default)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
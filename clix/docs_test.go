@@ -0,0 +1,120 @@
+package clix_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func testDocsApp() *cli.App {
+	prefix := clix.FlagPrefix("CLIX_TEST_DOCS_")
+	name := clix.NewFlagName(prefix, "", "name")
+	port := clix.NewFlagName(prefix, "serve", "port")
+
+	app := cli.NewApp()
+	app.Name = "example"
+	app.Usage = "an example"
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    name.Name,
+			Aliases: name.Aliases,
+			EnvVars: name.EnvVars,
+			Usage:   "the `name` | nickname",
+			Value:   "alice",
+		},
+		&cli.BoolFlag{Name: "verbose"},
+	}
+	app.Commands = []*cli.Command{
+		{
+			Name: "serve",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    port.Name,
+					Aliases: port.Aliases,
+					EnvVars: port.EnvVars,
+					Usage:   "listening port",
+				},
+			},
+		},
+		{
+			Name:   "debug",
+			Hidden: true,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "trace", EnvVars: []string{"CLIX_TEST_DOCS_TRACE"}},
+			},
+		},
+	}
+	clix.EnableDocsCommand(app)
+	return app
+}
+
+func TestDocs(t *testing.T) {
+	cases := []struct {
+		Format string
+		Want   string
+	}{
+		{
+			"markdown",
+			"# example environment variables\n" +
+				"\n" +
+				"## Global flags\n" +
+				"\n" +
+				"| Flag | Aliases | Environment variables | File variables | Default | Usage |\n" +
+				"|------|---------|-----------------------|----------------|---------|-------|\n" +
+				"| `--name` | `-n` | `CLIX_TEST_DOCS_NAME`, `CLIX_TEST_DOCS_N` | `CLIX_TEST_DOCS_NAME_FILE`, `CLIX_TEST_DOCS_N_FILE` | `alice` | the name \\| nickname |\n" +
+				"\n" +
+				"## Command `serve`\n" +
+				"\n" +
+				"| Flag | Aliases | Environment variables | File variables | Default | Usage |\n" +
+				"|------|---------|-----------------------|----------------|---------|-------|\n" +
+				"| `--serve-port` | `--serve-p` | `CLIX_TEST_DOCS_SERVE_PORT`, `CLIX_TEST_DOCS_SERVE_P` | `CLIX_TEST_DOCS_SERVE_PORT_FILE`, `CLIX_TEST_DOCS_SERVE_P_FILE` | `0` | listening port |\n",
+		},
+		{
+			"man",
+			`.TH EXAMPLE 1
+.SH NAME
+example \- an example
+.SH ENVIRONMENT
+.TP
+.B CLIX_TEST_DOCS_NAME, CLIX_TEST_DOCS_N
+Sets \fB\-\-name\fR. the name | nickname. Default: alice.
+.br
+The value is read from the file named by \fBCLIX_TEST_DOCS_NAME_FILE, CLIX_TEST_DOCS_N_FILE\fR.
+.TP
+.B CLIX_TEST_DOCS_SERVE_PORT, CLIX_TEST_DOCS_SERVE_P
+Sets \fBserve \-\-serve\-port\fR. listening port. Default: 0.
+.br
+The value is read from the file named by \fBCLIX_TEST_DOCS_SERVE_PORT_FILE, CLIX_TEST_DOCS_SERVE_P_FILE\fR.
+`,
+		},
+		{
+			"env",
+			`# --name: the name | nickname
+# also CLIX_TEST_DOCS_N
+# or the file named by CLIX_TEST_DOCS_NAME_FILE, CLIX_TEST_DOCS_N_FILE
+#CLIX_TEST_DOCS_NAME="alice"
+
+# serve --serve-port: listening port
+# also CLIX_TEST_DOCS_SERVE_P
+# or the file named by CLIX_TEST_DOCS_SERVE_PORT_FILE, CLIX_TEST_DOCS_SERVE_P_FILE
+#CLIX_TEST_DOCS_SERVE_PORT="0"
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Format, func(t *testing.T) {
+			var s strings.Builder
+			app := testDocsApp()
+			app.Writer = &s
+			if err := app.Run([]string{"example", "docs", "--format", c.Format}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.Want, s.String()); diff != "" {
+				t.Fatalf("-want +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package clix
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// DotEnv represents the flag of the paths of dotenv files, that fills the
+// environment variables of Prefix not set in the environment.
+//
+// Each line of the dotenv file is `KEY=VALUE`, optionally preceded by
+// `export`. The value may be quoted by double or single quotes.
+// The lines starting with '#' are comments.
+//
+// The variables are never set to the environment of the process. They are
+// kept in the context of the run of cli.App, so that the runs in parallel
+// do not share them. Since cli looks up only the environment while parsing
+// the flags, Before sets the flags of c by c.Set. Use Before in the Before of
+// the subcommands as well to set their flags, which reuses the variables
+// loaded by the parent command. The values are reported as SourceDotEnv.
+//
+// For the same reason, the flags with Required set only by the dotenv files
+// fail, since cli checks Required before Before. Use RequiresOneOf with
+// Validate of the FlagSet after Before instead of Required.
+//
+//	e.g.
+//	app.Before = clix.Chain(dotenv.Before, fs.Validate(clix.RequiresOneOf(flagName)))
+//
+// The environment variables `<NAME>_FILE` in the dotenv files are not used
+// by FlagPrefix.FilePath, since it is looked up when the flag is created.
+type DotEnv struct {
+	// Prefix is the prefix of the environment variables loaded.
+	// All the variables are loaded if Prefix is empty.
+	Prefix FlagPrefix
+
+	// FlagEnvFile is the paths of the dotenv files.
+	FlagEnvFile *cli.StringSliceFlag
+
+	// Defaults is the paths of the dotenv files loaded if FlagEnvFile is not
+	// set. The files that do not exist are skipped.
+	Defaults []string
+
+	// FlagSet is clix.FlagSet.
+	FlagSet FlagSet
+}
+
+// NewDotEnv returns *DotEnv loading ".env.local" and ".env" by default.
+func NewDotEnv(prefix FlagPrefix, group string) *DotEnv {
+	name := NewFlagNameAlias(prefix, group, "env-file", "envf")
+	return &DotEnv{
+		Prefix: prefix,
+		FlagEnvFile: &cli.StringSliceFlag{
+			Name:      name.Name,
+			Aliases:   name.Aliases,
+			Usage:     "dotenv `file`",
+			EnvVars:   name.EnvVars,
+			FilePath:  name.FilePath,
+			TakesFile: true,
+		},
+		Defaults: []string{".env.local", ".env"},
+		FlagSet:  NewFlagSet(),
+	}
+}
+
+// Flags returns []cli.Flag{d.FlagEnvFile}.
+func (d *DotEnv) Flags() []cli.Flag {
+	return []cli.Flag{d.FlagEnvFile}
+}
+
+// Before loads the dotenv files unless the parent command has loaded them,
+// then sets the values of the flags of c that are not set yet.
+// The variables loaded earlier take precedence.
+// Before is intended to be used as cli.BeforeFunc.
+//
+// Before should be called before ConfigFile.Before and the Init of the other
+// FlagSets in c, so that the dotenv files take precedence over the config
+// file, and the other FlagSets know the flags set by the dotenv files.
+func (d *DotEnv) Before(c *cli.Context) error {
+	if err := d.FlagSet.Init(c); err != nil {
+		return err
+	}
+
+	lookup := dotEnvLookupOf(c)
+	if !lookup.loaded(d) {
+		var err error
+		if lookup, err = d.load(c, lookup); err != nil {
+			return err
+		}
+		storeContext(c, dotEnvKey{}, lookup)
+	}
+
+	run := d.FlagSet.In(c)
+	for _, flag := range localFlags(c) {
+		if flag == cli.Flag(d.FlagEnvFile) || run.IsSetArgs(flag) {
+			continue
+		}
+		src := envOrFileSource(c, flag)
+		if src.Kind != SourceDotEnv {
+			continue
+		}
+		name := flag.Names()[0]
+		v, _ := lookup.find(flagStringSliceField(flag, "EnvVars"))
+		for _, v := range envValues(flag, v.value) {
+			if err := c.Set(name, v); err != nil {
+				return fmt.Errorf("%s: invalid value %q for flag %q: %w", src.Name, v, name, err)
+			}
+		}
+		recordSource(c, flag, src)
+		for _, name := range flag.Names() {
+			d.FlagSet.set(name, src)
+			run.set(name, src)
+		}
+	}
+	return nil
+}
+
+// load returns the lookup of the variables of Prefix in the dotenv files
+// given by the flag of c, or d.Defaults. parent is the lookup inherited from
+// the parent command, may be nil.
+func (d *DotEnv) load(c *cli.Context, parent *dotEnvLookup) (*dotEnvLookup, error) {
+	paths, optional := c.StringSlice(d.FlagEnvFile.Name), false
+	if len(paths) == 0 {
+		paths, optional = d.Defaults, true
+	}
+	vars := make(map[string]dotEnvVar)
+	for _, path := range paths {
+		if optional {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				continue
+			}
+		}
+		list, err := LoadDotEnv(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range list {
+			if !strings.HasPrefix(kv[0], string(d.Prefix)) {
+				continue
+			}
+			if _, ok := vars[kv[0]]; !ok {
+				vars[kv[0]] = dotEnvVar{path: path, value: kv[1]}
+			}
+		}
+	}
+	return &dotEnvLookup{dotenv: d, vars: vars, parent: parent}, nil
+}
+
+// dotEnvKey is the key of the context.Context of cli.Context, where the
+// variables loaded by DotEnv in the run are kept as *dotEnvLookup.
+type dotEnvKey struct{}
+
+// dotEnvVar is the variable loaded by DotEnv.
+type dotEnvVar struct {
+	path  string
+	value string
+}
+
+// dotEnvLookup is the variables loaded by DotEnv, and the ones loaded by the
+// parent commands. It is never changed once it is kept in the context.
+type dotEnvLookup struct {
+	dotenv *DotEnv
+	vars   map[string]dotEnvVar
+	parent *dotEnvLookup
+}
+
+// dotEnvLookupOf returns the lookup kept in the context of c, or nil.
+// c may be nil.
+func dotEnvLookupOf(c *cli.Context) *dotEnvLookup {
+	l, _ := loadContext(c, dotEnvKey{}).(*dotEnvLookup)
+	return l
+}
+
+// loaded returns true if d has loaded l or one of its parents.
+func (l *dotEnvLookup) loaded(d *DotEnv) bool {
+	for ; l != nil; l = l.parent {
+		if l.dotenv == d {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the variable env. The parents take precedence.
+func (l *dotEnvLookup) lookup(env string) (dotEnvVar, bool) {
+	if l == nil {
+		return dotEnvVar{}, false
+	}
+	if v, ok := l.parent.lookup(env); ok {
+		return v, true
+	}
+	v, ok := l.vars[env]
+	return v, ok
+}
+
+// find returns the first variable of envVars that is loaded.
+func (l *dotEnvLookup) find(envVars []string) (dotEnvVar, bool) {
+	for _, env := range envVars {
+		if v, ok := l.lookup(strings.TrimSpace(env)); ok {
+			return v, true
+		}
+	}
+	return dotEnvVar{}, false
+}
+
+// envValues returns the values of flag in the environment variable v,
+// split by "," for the slice flags as same as cli does.
+func envValues(flag cli.Flag, v string) []string {
+	switch flag.(type) {
+	case *cli.StringSliceFlag, *cli.IntSliceFlag, *cli.Int64SliceFlag, *cli.Float64SliceFlag:
+		list := strings.Split(v, ",")
+		for i, s := range list {
+			list[i] = strings.TrimSpace(s)
+		}
+		return list
+	}
+	return []string{v}
+}
+
+// LoadDotEnv returns the pairs of the names and the values of the variables
+// in the dotenv file of path, in order of appearance.
+func LoadDotEnv(path string) ([][2]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var vars [][2]string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		i := strings.IndexByte(line, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("%s:%d: missing '='", path, n)
+		}
+		key := strings.TrimSpace(line[:i])
+		if !isEnvName(key) {
+			return nil, fmt.Errorf("%s:%d: invalid name %q", path, n, key)
+		}
+		value, err := dotEnvValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		vars = append(vars, [2]string{key, value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// dotEnvValue returns the value of the right hand side of '='.
+func dotEnvValue(s string) (string, error) {
+	if len(s) == 0 {
+		return "", nil
+	}
+	switch q := s[0]; q {
+	case '\'':
+		i := strings.IndexByte(s[1:], q)
+		if i < 0 {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		return s[1 : i+1], nil
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch c := s[i]; c {
+			case '"':
+				return b.String(), nil
+			case '\\':
+				if i++; i == len(s) {
+					return "", fmt.Errorf("unterminated quote")
+				}
+				switch s[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(s[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated quote")
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// isEnvName returns true if s is a valid name of an environment variable.
+func isEnvName(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', 'A' <= r && r <= 'Z', 'a' <= r && r <= 'z':
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package clix_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLoadDotEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := `# comment
+A=1
+export B = two words # comment
+C="quoted # not comment\n"
+D='single \n'
+E=
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{
+		{"A", "1"},
+		{"B", "two words"},
+		{"C", "quoted # not comment\n"},
+		{"D", `single \n`},
+		{"E", ""},
+	}
+	got, err := clix.LoadDotEnv(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+
+	if err := os.WriteFile(path, []byte("A=1\nB\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clix.LoadDotEnv(path); err == nil || err.Error() != path+":2: missing '='" {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dev.env")
+	content := `CLIX_TEST_DOTENV_NAME=bob
+CLIX_TEST_DOTENV_PORT=8080
+CLIX_TEST_DOTENV_TAG=a,b
+CLIX_TEST_DOTENV_SERVE_HOST=example.com
+CLIX_TEST_DOTENV_OTHER_HOST=other.example.com
+CLIX_TEST_OTHER=other
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLIX_TEST_DOTENV_PORT", "80")
+
+	prefix := clix.FlagPrefix("CLIX_TEST_DOTENV_")
+	dotenv := clix.NewDotEnv(prefix, "")
+	flag := func(name string) *cli.StringFlag {
+		fn := clix.NewFlagName(prefix, "", name)
+		return &cli.StringFlag{Name: fn.Name, EnvVars: fn.EnvVars}
+	}
+	flagName, flagPort, flagHost := flag("name"), flag("port"), flag("serve-host")
+	flagOther := flag("other-host")
+	flagTag := &cli.StringSliceFlag{Name: "tag", EnvVars: []string{"CLIX_TEST_DOTENV_TAG"}}
+
+	type result struct {
+		Name, Port, Host string
+		Tag              []string
+		Sources          []string
+		Other            clix.ConfigEntry
+	}
+	var got result
+	app := cli.NewApp()
+	app.Flags = append(dotenv.Flags(), flagName, flagPort, flagTag)
+	app.Before = dotenv.Before
+	app.Commands = []*cli.Command{
+		{
+			Name:   "serve",
+			Flags:  []cli.Flag{flagHost},
+			Before: dotenv.Before,
+			Action: func(c *cli.Context) error {
+				fs := clix.NewLineageFlagSet()
+				if err := fs.Init(c); err != nil {
+					return err
+				}
+				got = result{
+					Name: c.String(flagName.Name),
+					Port: c.String(flagPort.Name),
+					Host: c.String(flagHost.Name),
+					Tag:  c.StringSlice(flagTag.Name),
+				}
+				for _, f := range []cli.Flag{flagName, flagPort, flagTag, flagHost} {
+					got.Sources = append(got.Sources, fs.Source(f).String())
+				}
+				for _, e := range clix.ConfigEntries(c) {
+					if e.Command == "other" {
+						got.Other = clix.ConfigEntry{Command: e.Command, Value: e.Value, Source: e.Source}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "other",
+			Flags: []cli.Flag{flagOther},
+		},
+	}
+	if err := app.Run([]string{"prog", "--env-file", path, "serve"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Name: "bob",
+		Port: "80",
+		Host: "example.com",
+		Tag:  []string{"a", "b"},
+		Sources: []string{
+			"dotenv:" + path,
+			"env:CLIX_TEST_DOTENV_PORT",
+			"dotenv:" + path,
+			"dotenv:" + path,
+		},
+		Other: clix.ConfigEntry{Command: "other", Value: "other.example.com", Source: "dotenv:" + path},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+	for _, name := range []string{
+		"CLIX_TEST_DOTENV_NAME",
+		"CLIX_TEST_DOTENV_TAG",
+		"CLIX_TEST_DOTENV_SERVE_HOST",
+		"CLIX_TEST_DOTENV_OTHER_HOST",
+		"CLIX_TEST_OTHER",
+	} {
+		if _, ok := os.LookupEnv(name); ok {
+			t.Errorf("%s is set to the environment", name)
+		}
+	}
+}
+
+func TestDotEnv_required(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("CLIX_TEST_DOTENV_REQ_NAME=bob\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(required bool) (string, error) {
+		prefix := clix.FlagPrefix("CLIX_TEST_DOTENV_REQ_")
+		dotenv := clix.NewDotEnv(prefix, "")
+		dotenv.Defaults = []string{path}
+		fn := clix.NewFlagName(prefix, "", "name")
+		flagName := &cli.StringFlag{Name: fn.Name, EnvVars: fn.EnvVars, Required: required}
+		fs := clix.NewFlagSet()
+
+		var got string
+		app := cli.NewApp()
+		app.Flags = clix.Flags(dotenv.Flags(), flagName)
+		app.Before = clix.Chain(dotenv.Before, fs.Validate(clix.RequiresOneOf(flagName)))
+		app.Action = func(c *cli.Context) error {
+			got = c.String(flagName.Name)
+			return nil
+		}
+		err := app.Run([]string{"prog"})
+		return got, err
+	}
+
+	// cli checks Required before DotEnv.Before loads the dotenv files.
+	if _, err := run(true); err == nil || err.Error() != `Required flag "name" not set` {
+		t.Errorf("Required: got %v", err)
+	}
+	if got, err := run(false); err != nil || got != "bob" {
+		t.Errorf("RequiresOneOf: got %q, %v", got, err)
+	}
+}
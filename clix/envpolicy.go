@@ -0,0 +1,45 @@
+package clix
+
+import "strings"
+
+// EnvPolicy is the policy of the names of the environment variables that
+// Naming.NewFlagName and Naming.NewFlagNameAlias make.
+//
+// The zero value is the default policy,
+// e.g. "server-address" of the group "api" => "<PREFIX>API_SERVER_ADDRESS".
+type EnvPolicy struct {
+	// LongNameOnly makes the environment variables of the names only,
+	// not of the aliases.
+	LongNameOnly bool
+
+	// Separator replaces "-" in the names, "_" if empty.
+	Separator string
+
+	// GroupSeparator joins the group and the name, Separator if empty.
+	//
+	//	e.g.
+	//	GroupSeparator "__" => "<PREFIX>API__SERVER_ADDRESS"
+	GroupSeparator string
+
+	// NoFile disables the lookup of the environment variables
+	// `<PREFIX><NAME>_FILE`.
+	NoFile bool
+}
+
+// key returns the name of the environment variable of name in group without
+// the prefix.
+func (p EnvPolicy) key(group, name string) string {
+	sep := p.Separator
+	if len(sep) == 0 {
+		sep = "_"
+	}
+	key := strings.ReplaceAll(strings.ToUpper(name), "-", sep)
+	if len(group) == 0 {
+		return key
+	}
+	groupSep := p.GroupSeparator
+	if len(groupSep) == 0 {
+		groupSep = sep
+	}
+	return strings.ReplaceAll(strings.ToUpper(group), "-", sep) + groupSep + key
+}
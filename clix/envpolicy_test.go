@@ -0,0 +1,109 @@
+package clix_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestNaming_Env(t *testing.T) {
+	prefix := clix.FlagPrefix("APP_")
+
+	os.Setenv("APP_SERVER_ADDRESS_FILE", os.Args[0])
+	t.Cleanup(func() { os.Unsetenv("APP_SERVER_ADDRESS_FILE") })
+	os.Setenv("APP_SERVER__ADDRESS_FILE", os.Args[0])
+	t.Cleanup(func() { os.Unsetenv("APP_SERVER__ADDRESS_FILE") })
+
+	cases := []struct {
+		Policy clix.EnvPolicy
+		Want   *clix.FlagName
+	}{
+		{
+			clix.EnvPolicy{},
+			&clix.FlagName{
+				Name:     "server-address",
+				Aliases:  []string{"server-a"},
+				EnvVars:  []string{"APP_SERVER_ADDRESS", "APP_SERVER_A"},
+				FilePath: os.Args[0],
+			},
+		},
+
+		{
+			clix.EnvPolicy{LongNameOnly: true},
+			&clix.FlagName{
+				Name:     "server-address",
+				Aliases:  []string{"server-a"},
+				EnvVars:  []string{"APP_SERVER_ADDRESS"},
+				FilePath: os.Args[0],
+			},
+		},
+
+		{
+			clix.EnvPolicy{GroupSeparator: "__"},
+			&clix.FlagName{
+				Name:     "server-address",
+				Aliases:  []string{"server-a"},
+				EnvVars:  []string{"APP_SERVER__ADDRESS", "APP_SERVER__A"},
+				FilePath: os.Args[0],
+			},
+		},
+
+		{
+			clix.EnvPolicy{Separator: "", GroupSeparator: "__", NoFile: true},
+			&clix.FlagName{
+				Name:    "server-address",
+				Aliases: []string{"server-a"},
+				EnvVars: []string{"APP_SERVER__ADDRESS", "APP_SERVER__A"},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		got := clix.Naming{Prefix: prefix, Env: c.Policy}.NewFlagName("server", "address")
+		if diff := cmp.Diff(c.Want, got); diff != "" {
+			t.Errorf("[%d] -want +got\n%s", i, diff)
+		}
+	}
+
+	t.Run("Separator", func(t *testing.T) {
+		naming := clix.Naming{
+			Prefix: clix.FlagPrefix("app."),
+			Env:    clix.EnvPolicy{Separator: ".", GroupSeparator: "/", LongNameOnly: true},
+		}
+		want := []string{"app.API.V1/TLS.CERT"}
+		got := naming.NewFlagName("api-v1", "tls-cert").EnvVars
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+	})
+
+	t.Run("Lint", func(t *testing.T) {
+		fn := clix.Naming{Prefix: prefix, Env: clix.EnvPolicy{GroupSeparator: "__"}}.NewFlagName("server", "address")
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{
+			&cli.StringFlag{Name: fn.Name, Aliases: fn.Aliases, EnvVars: fn.EnvVars, Usage: "address"},
+		}
+		if got := clix.Lint(app); len(got) > 0 {
+			t.Fatal(got)
+		}
+	})
+
+	t.Run("UseNaming", func(t *testing.T) {
+		naming := clix.Naming{Prefix: prefix, Env: clix.EnvPolicy{NoFile: true}}
+		fn := naming.NewFlagName("server", "address")
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{
+			&cli.StringFlag{Name: fn.Name, Aliases: fn.Aliases, EnvVars: fn.EnvVars, Usage: "address"},
+		}
+		if got := clix.DocEntries(app)[0].FileEnvVars; len(got) != 2 {
+			t.Fatalf("got %q, want 2 variables without UseNaming", got)
+		}
+		clix.UseNaming(app, naming)
+		if got := clix.DocEntries(app)[0].FileEnvVars; len(got) != 0 {
+			t.Fatalf("got %q, want none with NoFile", got)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package clix
+
+import "errors"
+
+// Errors is a list of errors reported together.
+//
+// Errors behaves as the error returned by errors.Join, and errors.Is and
+// errors.As look into each of the errors through Unwrap. It is a named type
+// so that the caller can get the list by errors.As, and so that ValidateAll
+// flattens the lists of the Validators into one.
+type Errors []error
+
+// Error returns the messages of the errors, one per line.
+func (e Errors) Error() string {
+	if err := errors.Join(e...); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// Unwrap returns the errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Err returns nil if e is empty, e[0] if e has only one error,
+// or e otherwise.
+func (e Errors) Err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	}
+	return e
+}
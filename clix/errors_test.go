@@ -0,0 +1,38 @@
+package clix_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/takumakei/go-urfave-cli/clix"
+)
+
+func TestErrors(t *testing.T) {
+	if err := clix.Errors(nil).Err(); err != nil {
+		t.Errorf("Err()=%v, want nil", err)
+	}
+	if err := (clix.Errors{io.EOF}).Err(); err != io.EOF {
+		t.Errorf("Err()=%v, want %v", err, io.EOF)
+	}
+
+	pathErr := &os.PathError{Op: "open", Path: "x", Err: os.ErrNotExist}
+	err := clix.Errors{io.EOF, pathErr}.Err()
+	if got, want := err.Error(), "EOF\nopen x: file does not exist"; got != want {
+		t.Errorf("Error()=%q, want %q", got, want)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Error("errors.Is(err, os.ErrNotExist)=false")
+	}
+	var target *os.PathError
+	if !errors.As(err, &target) || target != pathErr {
+		t.Errorf("errors.As(err, &target)=%v", target)
+	}
+}
+
+func TestErrors_empty(t *testing.T) {
+	if got := clix.Errors(nil).Error(); got != "" {
+		t.Errorf("Error()=%q, want empty", got)
+	}
+}
@@ -1,6 +1,11 @@
 package clix
 
-import "os"
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
 
 // FilePath returns the first file that exists
 // or an empty string if no file exists.
@@ -14,3 +19,162 @@ func FilePath(file ...string) string {
 	}
 	return ""
 }
+
+var (
+	// ErrIsDirectory represents an error where a candidate of the file is a
+	// directory.
+	ErrIsDirectory = errors.New("is a directory")
+
+	// ErrNotRegular represents an error where a candidate of the file is
+	// neither a regular file nor a directory, such as a named pipe.
+	ErrNotRegular = errors.New("is not a regular file")
+
+	// ErrNoMatch represents an error where no file matches a glob pattern.
+	ErrNoMatch = errors.New("no file matches")
+)
+
+// FilePathCandidate is a candidate of the file examined by ExplainFilePath.
+type FilePathCandidate struct {
+	// Pattern is the argument of ExplainFilePath.
+	Pattern string
+
+	// Path is the path expanded from Pattern,
+	// empty if no file matches Pattern.
+	Path string
+
+	// Err is the reason why the candidate is rejected,
+	// nil if the candidate is chosen.
+	Err error
+}
+
+// String returns the readable representation of c.
+func (c FilePathCandidate) String() string {
+	s := c.Pattern
+	if len(c.Path) > 0 && c.Path != c.Pattern {
+		s += " => " + c.Path
+	}
+	if c.Err != nil {
+		return s + ": " + c.Err.Error()
+	}
+	return s + ": ok"
+}
+
+// ReadableFilePath returns the first readable regular file
+// or an empty string if no file is readable.
+//
+// Unlike FilePath, directories and unreadable files are skipped,
+// and each file is expanded by ExpandPath and filepath.Glob.
+func ReadableFilePath(file ...string) string {
+	path, _ := ExplainFilePath(file...)
+	return path
+}
+
+// ExplainFilePath returns the same path as ReadableFilePath,
+// and the candidates examined with the reasons why they are rejected.
+// The empty strings in file are ignored.
+func ExplainFilePath(file ...string) (string, []FilePathCandidate) {
+	var list []FilePathCandidate
+	for _, pattern := range file {
+		if len(pattern) == 0 {
+			continue
+		}
+		path := ExpandPath(pattern)
+		if !hasGlobMeta(path) {
+			err := readable(path)
+			list = append(list, FilePathCandidate{Pattern: pattern, Path: path, Err: err})
+			if err == nil {
+				return path, list
+			}
+			continue
+		}
+		matches, err := filepath.Glob(path)
+		if err == nil && len(matches) == 0 {
+			err = ErrNoMatch
+		}
+		if err != nil {
+			list = append(list, FilePathCandidate{Pattern: pattern, Err: err})
+			continue
+		}
+		for _, m := range matches {
+			err := readable(m)
+			list = append(list, FilePathCandidate{Pattern: pattern, Path: m, Err: err})
+			if err == nil {
+				return m, list
+			}
+		}
+	}
+	return "", list
+}
+
+// readable returns nil if path is a regular file that can be opened.
+func readable(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return ErrIsDirectory
+	}
+	// Opening a named pipe blocks until a writer opens it.
+	if !fi.Mode().IsRegular() {
+		return ErrNotRegular
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// hasGlobMeta returns true if path has any of the special characters of
+// filepath.Match.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[`)
+}
+
+// ExpandPath replaces the leading `~` with the home directory, and
+// `$VAR` or `${VAR}` with the environment variables.
+func ExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
+		}
+	}
+	return path
+}
+
+// XDGFilePaths returns the paths of `<app>/defaults/<name>` in
+// $XDG_CONFIG_HOME and $XDG_CONFIG_DIRS in order.
+//
+// $XDG_CONFIG_HOME defaults to `$HOME/.config`,
+// and $XDG_CONFIG_DIRS defaults to `/etc/xdg`.
+func XDGFilePaths(app, name string) []string {
+	var list []string
+	rel := filepath.Join(app, "defaults", name)
+	if dir := userConfigDir(); len(dir) > 0 {
+		list = append(list, filepath.Join(dir, rel))
+	}
+	dirs := os.Getenv("XDG_CONFIG_DIRS")
+	if len(dirs) == 0 {
+		dirs = "/etc/xdg"
+	}
+	for _, dir := range filepath.SplitList(dirs) {
+		if filepath.IsAbs(dir) {
+			list = append(list, filepath.Join(dir, rel))
+		}
+	}
+	return list
+}
+
+// userConfigDir returns $XDG_CONFIG_HOME, or `$HOME/.config` if it is not an
+// absolute path. It returns an empty string if neither is available.
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config")
+	}
+	return ""
+}
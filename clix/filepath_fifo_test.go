@@ -0,0 +1,26 @@
+//go:build !windows
+
+package clix_test
+
+import (
+	"errors"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/takumakei/go-urfave-cli/clix"
+)
+
+func TestExplainFilePath_fifo(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "fifo")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Skip(err)
+	}
+	got, candidates := clix.ExplainFilePath(fifo)
+	if got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+	if len(candidates) != 1 || !errors.Is(candidates[0].Err, clix.ErrNotRegular) {
+		t.Errorf("candidates=%v", candidates)
+	}
+}
@@ -1,11 +1,13 @@
 package clix_test
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/takumakei/go-urfave-cli/clix"
 	"github.com/urfave/cli/v2"
 )
@@ -115,3 +117,86 @@ func testFilePath(t *testing.T, want string, args []string, f2nd, f3rd string) {
 		t.Errorf("got %q, want %q", got, want)
 	}
 }
+
+func TestExplainFilePath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b.pem", "c.pem"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Setenv("CLIX_TEST_EXPLAIN_DIR", dir)
+	t.Cleanup(func() { os.Unsetenv("CLIX_TEST_EXPLAIN_DIR") })
+
+	got, candidates := clix.ExplainFilePath(
+		"",
+		"$CLIX_TEST_EXPLAIN_DIR/none",
+		"${CLIX_TEST_EXPLAIN_DIR}/sub",
+		"$CLIX_TEST_EXPLAIN_DIR/*.key",
+		"$CLIX_TEST_EXPLAIN_DIR/*.pem",
+		"$CLIX_TEST_EXPLAIN_DIR/never",
+	)
+	if want := filepath.Join(dir, "b.pem"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	type result struct {
+		Path     string
+		Rejected bool
+	}
+	want := []result{
+		{filepath.Join(dir, "none"), true},
+		{filepath.Join(dir, "sub"), true},
+		{"", true},
+		{filepath.Join(dir, "b.pem"), false},
+	}
+	results := make([]result, len(candidates))
+	for i, c := range candidates {
+		results[i] = result{c.Path, c.Err != nil}
+	}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+	if !errors.Is(candidates[0].Err, os.ErrNotExist) {
+		t.Errorf("candidates[0].Err=%v", candidates[0].Err)
+	}
+	if !errors.Is(candidates[1].Err, clix.ErrIsDirectory) {
+		t.Errorf("candidates[1].Err=%v", candidates[1].Err)
+	}
+	if !errors.Is(candidates[2].Err, clix.ErrNoMatch) {
+		t.Errorf("candidates[2].Err=%v", candidates[2].Err)
+	}
+	if got := clix.ReadableFilePath(filepath.Join(dir, "sub")); got != "" {
+		t.Errorf("ReadableFilePath(dir)=%q, want empty", got)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip(err)
+	}
+	if got, want := clix.ExpandPath("~/.config"), filepath.Join(home, ".config"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := clix.ExpandPath("a/~"), "a/~"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestXDGFilePaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/alice/.xdg")
+	t.Setenv("XDG_CONFIG_DIRS", "/etc/xdg:relative:/usr/local/etc")
+	want := []string{
+		"/home/alice/.xdg/example/defaults/password",
+		"/etc/xdg/example/defaults/password",
+		"/usr/local/etc/example/defaults/password",
+	}
+	got := clix.XDGFilePaths("example", "password")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
@@ -9,9 +9,9 @@ type FlagName struct {
 	FilePath string
 }
 
-// NewFlagName returns *FlagName.
+// NewFlagName returns Naming{Prefix: prefix}.NewFlagName(group, name).
 func NewFlagName(prefix FlagPrefix, group, name string) *FlagName {
-	return NewFlagNameAlias(prefix, group, name, ShortFlagName(name))
+	return Naming{Prefix: prefix}.NewFlagName(group, name)
 }
 
 // ShortFlagName makes a short flag name.
@@ -28,20 +28,8 @@ func ShortFlagName(name string) string {
 	return string(a)
 }
 
-// NewFlagNameAlias returns *FlagName.
+// NewFlagNameAlias returns Naming{Prefix: prefix}.NewFlagNameAlias(group,
+// name, alias).
 func NewFlagNameAlias(prefix FlagPrefix, group, name, alias string) *FlagName {
-	if len(group) > 0 {
-		name = group + "-" + name
-		alias = group + "-" + alias
-	}
-
-	uf := strings.ReplaceAll(strings.ToUpper(name), "-", "_")
-	ua := strings.ReplaceAll(strings.ToUpper(alias), "-", "_")
-
-	return &FlagName{
-		Name:     name,
-		Aliases:  []string{alias},
-		EnvVars:  prefix.EnvVars(uf, ua),
-		FilePath: prefix.FilePath(uf, ua),
-	}
+	return Naming{Prefix: prefix}.NewFlagNameAlias(group, name, alias)
 }
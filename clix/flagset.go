@@ -4,31 +4,189 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/urfave/cli/v2"
 )
 
-// FlagSet represents ...
-type FlagSet map[string]struct{}
+// FlagSet represents the flags set in the context,
+// and where their values came from.
+//
+// The zero value FlagSet{} is ready to use, as well as NewFlagSet(), but a nil
+// FlagSet has no flags set and must not be initialized.
+//
+// The copies of FlagSet share the same state, and it is safe to use them
+// from multiple goroutines. Each Init replaces the state, so that the same
+// FlagSet can be reused across multiple runs of cli.App. The state reflects
+// the last Init, so use In to get the state of a run when the runs may be
+// concurrent.
+type FlagSet map[flagSetSlot]*flagSetState
+
+// flagSetSlot is the only key of FlagSet, where the state is kept.
+type flagSetSlot struct{}
+
+type flagSetState struct {
+	mu      sync.RWMutex
+	sources FlagSetSnapshot
+	c       *cli.Context
+}
+
+// flagSetMu guards the state of FlagSet allocated lazily.
+var flagSetMu sync.RWMutex
+
+// FlagSetSnapshot is the state of FlagSet, the sources of the flags set in
+// the context by their names.
+type FlagSetSnapshot map[string]Source
 
 // NewFlagSet returns a FlagSet.
 func NewFlagSet() FlagSet {
-	return make(FlagSet)
+	return newFlagSet(make(FlagSetSnapshot), nil)
+}
+
+// newFlagSet returns FlagSet of sources, bound to c.
+func newFlagSet(sources FlagSetSnapshot, c *cli.Context) FlagSet {
+	return FlagSet{{}: &flagSetState{sources: sources, c: c}}
+}
+
+// state returns the state of fs, or allocates it if fs is FlagSet{}.
+// It returns nil if fs is nil.
+func (fs FlagSet) state() *flagSetState {
+	if fs == nil {
+		return nil
+	}
+	flagSetMu.RLock()
+	st := fs[flagSetSlot{}]
+	flagSetMu.RUnlock()
+	if st != nil {
+		return st
+	}
+
+	flagSetMu.Lock()
+	defer flagSetMu.Unlock()
+	if st = fs[flagSetSlot{}]; st == nil {
+		st = &flagSetState{sources: make(FlagSetSnapshot)}
+		fs[flagSetSlot{}] = st
+	}
+	return st
 }
 
-// Init initialize fs using c.LocalFlagNames().
+// flagSetKey is the key of the FlagSet of a run in the context.Context of
+// cli.Context, keyed by the state of the FlagSet that made it.
+type flagSetKey struct{ state *flagSetState }
+
+// Init resets fs, then initializes it using c.LocalFlagNames().
+//
+// Init also keeps a new FlagSet of the run of c in c.Context, that In
+// returns.
 func (fs FlagSet) Init(c *cli.Context) error {
+	run := newRunFlagSet(c)
+	fs.Restore(run.Snapshot())
+	fs.bind(c)
+	storeContext(c, flagSetKey{fs.state()}, run)
+	return nil
+}
+
+// newRunFlagSet returns FlagSet initialized using c.LocalFlagNames(),
+// bound to c.
+func newRunFlagSet(c *cli.Context) FlagSet {
+	sources := make(FlagSetSnapshot)
 	for _, name := range c.LocalFlagNames() {
-		fs[name] = struct{}{}
+		sources[name] = recordedSource(c, name)
 	}
-	return nil
+	return newFlagSet(sources, c)
+}
+
+// In returns the FlagSet of the run of c that fs.Init(c) made, or fs if
+// fs.Init has been called in neither c nor its parents.
+//
+// Unlike fs, the FlagSet returned is not changed by the other runs.
+func (fs FlagSet) In(c *cli.Context) FlagSet {
+	if run, ok := loadContext(c, flagSetKey{fs.state()}).(FlagSet); ok {
+		return run
+	}
+	return fs
+}
+
+// Reset forgets all the flags set.
+func (fs FlagSet) Reset() {
+	fs.Restore(nil)
+}
+
+// Snapshot returns a copy of the state of fs,
+// or nil if fs is nil.
+func (fs FlagSet) Snapshot() FlagSetSnapshot {
+	st := fs.state()
+	if st == nil {
+		return nil
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.sources.clone()
+}
+
+// Restore replaces the state of fs with a copy of snapshot.
+func (fs FlagSet) Restore(snapshot FlagSetSnapshot) {
+	sources := snapshot.clone()
+	st := fs.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sources = sources
+}
+
+// bind keeps c, so that the sources of the flags not set in c are looked up
+// in the run of c.
+func (fs FlagSet) bind(c *cli.Context) {
+	st := fs.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.c = c
+}
+
+// context returns the context passed to bind, or nil.
+func (fs FlagSet) context() *cli.Context {
+	st := fs.state()
+	if st == nil {
+		return nil
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.c
+}
+
+// set sets src as the source of the flag named name.
+func (fs FlagSet) set(name string, src Source) {
+	st := fs.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sources[name] = src
+}
+
+// lookup returns the source of the flag named name if it is set.
+func (fs FlagSet) lookup(name string) (Source, bool) {
+	st := fs.state()
+	if st == nil {
+		return Source{}, false
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	s, ok := st.sources[name]
+	return s, ok
+}
+
+// clone returns a copy of s, never nil.
+func (s FlagSetSnapshot) clone() FlagSetSnapshot {
+	c := make(FlagSetSnapshot, len(s))
+	for k, v := range s {
+		c[k] = v
+	}
+	return c
 }
 
 // IsSetArgs returns true if flag is specified in arguments
 // or false otherwise.
 func (fs FlagSet) IsSetArgs(flag cli.Flag) bool {
 	for _, name := range flag.Names() {
-		if _, ok := fs[name]; ok {
+		if s, ok := fs.lookup(name); ok && s.Kind == SourceArg {
 			return true
 		}
 	}
@@ -40,12 +198,33 @@ func (fs FlagSet) IsSetArgs(flag cli.Flag) bool {
 //   - specified by arguments
 //   - specified by EnvVars
 //   - specified by FilePath
+//   - specified by ConfigFile
 func (fs FlagSet) IsSet(flag cli.Flag) bool {
-	// flag.IsSet() returns true in following cases.
+	// isSetEnvs(flag) returns true in following cases.
 	//   - specified by EnvVars
 	//   - specified by FilePath
-	// fs.IsSetArgs(flag) returns true if it exists in arguments.
-	return flag.IsSet() || fs.IsSetArgs(flag)
+	// fs.isSetLocal(flag) returns true if it exists in arguments or it is
+	// set by ConfigFile.
+	return isSetEnvs(fs.context(), flag) || fs.isSetLocal(flag)
+}
+
+// isSetLocal returns true if flag is in fs.
+func (fs FlagSet) isSetLocal(flag cli.Flag) bool {
+	for _, name := range flag.Names() {
+		if _, ok := fs.lookup(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSetEnvs returns true if flag is specified by EnvVars or FilePath, or the
+// variables loaded by DotEnv in the run of c. c may be nil.
+//
+// *cli.GenericFlag never reports IsSet() because its Apply has a value
+// receiver, so the sources are looked up as well.
+func isSetEnvs(c *cli.Context, flag cli.Flag) bool {
+	return flag.IsSet() || envOrFileSource(c, flag).Kind != SourceDefault
 }
 
 var (
@@ -69,7 +248,7 @@ var (
 // returns err!=nil if and only if multiple flags are set at the same time.
 //
 // At first command line arguments are searched.
-// Next the environment variables (include FilePath) are searched.
+// Next the environment variables (include FilePath and ConfigFile) are searched.
 // At most one flag can be specified at the same time,
 // otherwise err!=nil is returned.
 func (fs FlagSet) Exclusive(flag ...cli.Flag) (cli.Flag, error) {
@@ -85,10 +264,12 @@ func (fs FlagSet) Exclusive(flag ...cli.Flag) (cli.Flag, error) {
 	}
 
 	p.reset()
-	// f.IsSet() returns true in following cases.
+	// fs.IsSet(f) returns true in following cases,
+	// since none of flag is specified in arguments.
 	//   - specified by EnvVars
 	//   - specified by FilePath
-	n = p.pick(flag, func(f cli.Flag) bool { return f.IsSet() })
+	//   - specified by ConfigFile
+	n = p.pick(flag, fs.IsSet)
 	switch {
 	case n == 1:
 		f := p.found[0]
@@ -3,9 +3,11 @@ package clix_test
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -155,3 +157,175 @@ func TestErrExclusiveFlags(t *testing.T) {
 		}
 	})
 }
+
+func testFlagSetApp(fs clix.FlagSet, action func(*cli.Context) error) *cli.App {
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.IntFlag{Name: "a"},
+		&cli.IntFlag{Name: "b"},
+	}
+	app.Writer = io.Discard
+	app.ErrWriter = io.Discard
+	app.Before = fs.Init
+	app.Action = action
+	return app
+}
+
+func TestFlagSet_Reuse(t *testing.T) {
+	fs := clix.NewFlagSet()
+	var got []clix.FlagSetSnapshot
+	app := testFlagSetApp(fs, func(c *cli.Context) error {
+		got = append(got, fs.Snapshot())
+		return nil
+	})
+	for _, args := range [][]string{{"prog", "-a", "1"}, {"prog", "-b", "2"}, {"prog"}} {
+		if err := app.Run(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []clix.FlagSetSnapshot{
+		{"a": {Kind: clix.SourceArg, Name: "a"}},
+		{"b": {Kind: clix.SourceArg, Name: "b"}},
+		{},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func TestFlagSet_SnapshotRestore(t *testing.T) {
+	flagA := &cli.IntFlag{Name: "a"}
+	fs := clix.NewFlagSet()
+	app := testFlagSetApp(fs, func(*cli.Context) error { return nil })
+	if err := app.Run([]string{"prog", "-a", "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := fs.Snapshot()
+	fs.Reset()
+	if fs.IsSet(flagA) {
+		t.Fatal("IsSet after Reset")
+	}
+
+	fs.Restore(snapshot)
+	if !fs.IsSetArgs(flagA) {
+		t.Fatal("not IsSetArgs after Restore")
+	}
+
+	// the snapshot is not affected by the later changes.
+	fs.Reset()
+	if diff := cmp.Diff(clix.FlagSetSnapshot{"a": {Kind: clix.SourceArg, Name: "a"}}, snapshot); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func TestFlagSet_In(t *testing.T) {
+	flagA := &cli.IntFlag{Name: "a"}
+	flagB := &cli.IntFlag{Name: "b"}
+	fs := clix.NewFlagSet()
+	var app *cli.App
+	app = testFlagSetApp(fs, func(c *cli.Context) error {
+		if !c.IsSet("a") {
+			return nil
+		}
+		// another run inits fs before this run looks up the flags.
+		if err := app.Run([]string{"prog", "-b", "1"}); err != nil {
+			return err
+		}
+		if !fs.IsSet(flagB) {
+			return fmt.Errorf("fs.IsSet(b)=false after the other run")
+		}
+		if a, b := fs.In(c).IsSet(flagA), fs.In(c).IsSet(flagB); !a || b {
+			return fmt.Errorf("fs.In(c).IsSet(a)=%v, fs.In(c).IsSet(b)=%v", a, b)
+		}
+		return nil
+	})
+	if err := app.Run([]string{"prog", "-a", "1"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFlagSet_Parallel(t *testing.T) {
+	flagA := &cli.IntFlag{Name: "a"}
+	flagB := &cli.IntFlag{Name: "b"}
+	fs := clix.NewFlagSet()
+	app := testFlagSetApp(fs, func(c *cli.Context) error {
+		// Each run sets exactly one of the flags, so the state of the run
+		// must have exactly the one set by its own arguments.
+		run := fs.In(c)
+		wantA := c.IsSet("a")
+		if a, b := run.IsSet(flagA), run.IsSet(flagB); a != wantA || b == wantA {
+			return fmt.Errorf("IsSet(a)=%v, IsSet(b)=%v, want IsSet(a)=%v", a, b, wantA)
+		}
+		if n := len(run.Snapshot()); n != 1 {
+			return fmt.Errorf("len(Snapshot())=%d", n)
+		}
+		return nil
+	})
+	// the first run sets up app.
+	if err := app.Run([]string{"prog", "-a", "0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 100)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			args := []string{"prog", "-a", strconv.Itoa(i)}
+			if i%2 == 1 {
+				args[1] = "-b"
+			}
+			errs[i] = app.Run(args)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("[%d] %v", i, err)
+		}
+	}
+}
+
+func TestFlagSet_ZeroValue(t *testing.T) {
+	flagA := &cli.IntFlag{Name: "a"}
+
+	var none clix.FlagSet
+	if none.IsSet(flagA) || none.Snapshot() != nil {
+		t.Fatal("nil FlagSet has flags set")
+	}
+
+	fs := clix.FlagSet{}
+	copied := fs
+	app := testFlagSetApp(fs, func(c *cli.Context) error {
+		if !fs.In(c).IsSetArgs(flagA) {
+			return fmt.Errorf("fs.In(c).IsSetArgs(a)=false")
+		}
+		return nil
+	})
+	if err := app.Run([]string{"prog", "-a", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if !copied.IsSetArgs(flagA) {
+		t.Fatal("the copy of FlagSet{} does not share the state")
+	}
+}
+
+func TestFlagSet_InitParallel(t *testing.T) {
+	flagA := &cli.IntFlag{Name: "a"}
+	flagB := &cli.IntFlag{Name: "b"}
+	a, b := clix.NewFlagSet(), clix.NewFlagSet()
+	app := testFlagSetApp(a, func(c *cli.Context) error {
+		if !a.In(c).IsSetArgs(flagA) || !b.In(c).IsSetArgs(flagB) {
+			return fmt.Errorf("the run of a or b is lost")
+		}
+		return nil
+	})
+	app.Before = clix.Parallel(a.Init, b.Init)
+	for i := 0; i < 10; i++ {
+		if err := app.Run([]string{"prog", "-a", "1", "-b", "2"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
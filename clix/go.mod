@@ -1,11 +1,16 @@
 module github.com/takumakei/go-urfave-cli/clix
 
-go 1.16
+go 1.21
 
 require (
-	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
+	github.com/BurntSushi/toml v0.3.1
 	github.com/google/go-cmp v0.5.5
-	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/takumakei/go-exit v0.0.0-20210429095029-8c3e71abac7f
 	github.com/urfave/cli/v2 v2.3.0
+	gopkg.in/yaml.v2 v2.2.3
+)
+
+require (
+	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 )
@@ -0,0 +1,168 @@
+package clix
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Group builds flags sharing the same FlagPrefix and group name.
+//
+// Each constructor fills Name, Aliases, EnvVars and FilePath of the given
+// flag in the same way as Naming.NewFlagName and Naming.NewFlagNameAlias of
+// the Naming that made the group do, sets
+// Destination if it is nil, and registers the flag to the group.
+//
+// The Name of the given flag is the name without the group name.
+// If the given flag has Aliases, the first one is used as the alias,
+// otherwise ShortFlagName(Name) is used.
+type Group struct {
+	// Prefix is the prefix of the environment variables.
+	Prefix FlagPrefix
+
+	// Name is the name of the group, may be empty string.
+	Name string
+
+	// FlagSet is clix.FlagSet shared by the flags in the group.
+	FlagSet FlagSet
+
+	naming      Naming
+	flags       []cli.Flag
+	constraints []Constraint
+}
+
+// NewGroup returns Naming{Prefix: prefix}.NewGroup(name).
+func NewGroup(prefix FlagPrefix, name string) *Group {
+	return Naming{Prefix: prefix}.NewGroup(name)
+}
+
+// Flags returns the flags registered to g in order.
+func (g *Group) Flags() []cli.Flag {
+	return append([]cli.Flag(nil), g.flags...)
+}
+
+// Constrain registers the constraints checked by g.Validate.
+func (g *Group) Constrain(constraint ...Constraint) {
+	g.constraints = append(g.constraints, constraint...)
+}
+
+// Validate calls g.FlagSet.Init(c), then checks all the constraints
+// registered by g.Constrain, returns all the violations together as Errors.
+func (g *Group) Validate(c *cli.Context) error {
+	return g.FlagSet.Validate(g.constraints...)(c)
+}
+
+// Before calls g.Validate(c).
+// Before is intended to be used as cli.BeforeFunc.
+func (g *Group) Before(c *cli.Context) error {
+	return g.Validate(c)
+}
+
+// flagName returns *FlagName for name and aliases.
+func (g *Group) flagName(name string, aliases []string) *FlagName {
+	n := g.naming
+	n.Prefix = g.Prefix
+	if len(aliases) == 0 {
+		return n.NewFlagName(g.Name, name)
+	}
+	fn := n.NewFlagNameAlias(g.Name, name, aliases[0])
+	for _, alias := range aliases[1:] {
+		v := n.NewFlagNameAlias(g.Name, name, alias)
+		fn.Aliases = append(fn.Aliases, v.Aliases...)
+		fn.EnvVars = append(fn.EnvVars, v.EnvVars[1:]...)
+	}
+	return fn
+}
+
+// fill overwrites the names of a flag with the result of g.flagName.
+func (g *Group) fill(name *string, aliases, envVars *[]string, filePath *string) {
+	fn := g.flagName(*name, *aliases)
+	*name, *aliases, *envVars, *filePath = fn.Name, fn.Aliases, fn.EnvVars, fn.FilePath
+}
+
+// register fills the names of flag and appends flag to g.
+func (g *Group) register(flag cli.Flag, name *string, aliases, envVars *[]string, filePath *string) {
+	g.fill(name, aliases, envVars, filePath)
+	g.flags = append(g.flags, flag)
+}
+
+// String registers flag, returns *StringVar.
+func (g *Group) String(flag *cli.StringFlag) *StringVar {
+	g.register(flag, &flag.Name, &flag.Aliases, &flag.EnvVars, &flag.FilePath)
+	return &StringVar{Var: *NewStringVar(flag, g.FlagSet), Flag: flag}
+}
+
+// Bool registers flag, returns *BoolVar.
+func (g *Group) Bool(flag *cli.BoolFlag) *BoolVar {
+	g.register(flag, &flag.Name, &flag.Aliases, &flag.EnvVars, &flag.FilePath)
+	return &BoolVar{Var: *NewBoolVar(flag, g.FlagSet), Flag: flag}
+}
+
+// Int registers flag, returns *IntVar.
+func (g *Group) Int(flag *cli.IntFlag) *IntVar {
+	g.register(flag, &flag.Name, &flag.Aliases, &flag.EnvVars, &flag.FilePath)
+	return &IntVar{Var: *NewIntVar(flag, g.FlagSet), Flag: flag}
+}
+
+// Duration registers flag, returns *DurationVar.
+func (g *Group) Duration(flag *cli.DurationFlag) *DurationVar {
+	g.register(flag, &flag.Name, &flag.Aliases, &flag.EnvVars, &flag.FilePath)
+	return &DurationVar{Var: *NewDurationVar(flag, g.FlagSet), Flag: flag}
+}
+
+// StringSlice registers flag, returns *StringSliceVar.
+func (g *Group) StringSlice(flag *cli.StringSliceFlag) *StringSliceVar {
+	g.register(flag, &flag.Name, &flag.Aliases, &flag.EnvVars, &flag.FilePath)
+	return &StringSliceVar{Var: *NewStringSliceVar(flag, g.FlagSet), Flag: flag}
+}
+
+// Generic registers flag, returns *GenericVar.
+// flag.Value must not be nil.
+func (g *Group) Generic(flag *cli.GenericFlag) *GenericVar {
+	g.register(flag, &flag.Name, &flag.Aliases, &flag.EnvVars, &flag.FilePath)
+	v := NewVar(flag, g.FlagSet, func() cli.Generic { return flag.Value })
+	return &GenericVar{Var: *v, Flag: flag}
+}
+
+// GroupGeneric registers flag to g, returns NewGenericVar[T](flag, g.FlagSet).
+func GroupGeneric[T any](g *Group, flag *cli.GenericFlag) *Var[T] {
+	v := NewGenericVar[T](flag, g.FlagSet)
+	g.register(flag, &flag.Name, &flag.Aliases, &flag.EnvVars, &flag.FilePath)
+	return v
+}
+
+// StringVar is a *cli.StringFlag registered to a Group.
+type StringVar struct {
+	Var[string]
+	Flag *cli.StringFlag
+}
+
+// BoolVar is a *cli.BoolFlag registered to a Group.
+type BoolVar struct {
+	Var[bool]
+	Flag *cli.BoolFlag
+}
+
+// IntVar is a *cli.IntFlag registered to a Group.
+type IntVar struct {
+	Var[int]
+	Flag *cli.IntFlag
+}
+
+// DurationVar is a *cli.DurationFlag registered to a Group.
+type DurationVar struct {
+	Var[time.Duration]
+	Flag *cli.DurationFlag
+}
+
+// StringSliceVar is a *cli.StringSliceFlag registered to a Group.
+type StringSliceVar struct {
+	Var[[]string]
+	Flag *cli.StringSliceFlag
+}
+
+// GenericVar is a *cli.GenericFlag registered to a Group.
+type GenericVar struct {
+	Var[cli.Generic]
+	Flag *cli.GenericFlag
+}
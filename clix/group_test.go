@@ -0,0 +1,119 @@
+package clix_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestGroup(t *testing.T) {
+	group := clix.NewGroup(clix.FlagPrefix("EXAMPLE_"), "server")
+
+	var (
+		flagAddress = &cli.StringFlag{
+			Name:    "address",
+			Aliases: []string{"addr"},
+			Usage:   "address to listen",
+			Value:   "localhost:8080",
+		}
+		flagVerbose = &cli.BoolFlag{Name: "verbose"}
+		flagCount   = &cli.IntFlag{Name: "count", Value: 3}
+		flagTimeout = &cli.DurationFlag{Name: "timeout"}
+		flagPaths   = &cli.StringSliceFlag{Name: "path", Aliases: []string{"p", "dir"}}
+		flagLevel   = &cli.GenericFlag{Name: "level", Value: &cli.StringSlice{}}
+	)
+
+	address := group.String(flagAddress)
+	verbose := group.Bool(flagVerbose)
+	count := group.Int(flagCount)
+	timeout := group.Duration(flagTimeout)
+	paths := group.StringSlice(flagPaths)
+	level := group.Generic(flagLevel)
+
+	t.Run("Names", func(t *testing.T) {
+		type names struct {
+			Names   []string
+			EnvVars []string
+		}
+		want := []names{
+			{[]string{"server-address", "server-addr"}, []string{"EXAMPLE_SERVER_ADDRESS", "EXAMPLE_SERVER_ADDR"}},
+			{[]string{"server-verbose", "server-v"}, []string{"EXAMPLE_SERVER_VERBOSE", "EXAMPLE_SERVER_V"}},
+			{[]string{"server-count", "server-c"}, []string{"EXAMPLE_SERVER_COUNT", "EXAMPLE_SERVER_C"}},
+			{[]string{"server-timeout", "server-t"}, []string{"EXAMPLE_SERVER_TIMEOUT", "EXAMPLE_SERVER_T"}},
+			{[]string{"server-path", "server-p", "server-dir"}, []string{"EXAMPLE_SERVER_PATH", "EXAMPLE_SERVER_P", "EXAMPLE_SERVER_DIR"}},
+			{[]string{"server-level", "server-l"}, []string{"EXAMPLE_SERVER_LEVEL", "EXAMPLE_SERVER_L"}},
+		}
+		got := []names{
+			{flagAddress.Names(), flagAddress.EnvVars},
+			{flagVerbose.Names(), flagVerbose.EnvVars},
+			{flagCount.Names(), flagCount.EnvVars},
+			{flagTimeout.Names(), flagTimeout.EnvVars},
+			{flagPaths.Names(), flagPaths.EnvVars},
+			{flagLevel.Names(), flagLevel.EnvVars},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+		if address.Flag != flagAddress || level.Flag != flagLevel {
+			t.Fatal("Flag is not the registered flag")
+		}
+		if n := len(group.Flags()); n != len(want) {
+			t.Fatalf("len(Flags())=%d, want %d", n, len(want))
+		}
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		os.Setenv("EXAMPLE_SERVER_TIMEOUT", "3s")
+		t.Cleanup(func() { os.Unsetenv("EXAMPLE_SERVER_TIMEOUT") })
+
+		type value struct {
+			Address    string
+			AddressSet bool
+			Verbose    bool
+			Count      int
+			CountSet   bool
+			Timeout    time.Duration
+			TimeoutSet bool
+			TimeoutSrc string
+			Paths      []string
+			Level      string
+		}
+		want := value{
+			Address:    "localhost:8080",
+			AddressSet: false,
+			Verbose:    true,
+			Count:      3,
+			CountSet:   false,
+			Timeout:    3 * time.Second,
+			TimeoutSet: true,
+			TimeoutSrc: "env:EXAMPLE_SERVER_TIMEOUT",
+			Paths:      []string{"a", "b"},
+			Level:      "[x]",
+		}
+		var got value
+		app := cli.NewApp()
+		app.Flags = group.Flags()
+		app.Before = group.Before
+		app.Action = func(c *cli.Context) error {
+			got.Address, got.AddressSet = address.Lookup()
+			got.Verbose = verbose.Value()
+			got.Count, got.CountSet = count.Lookup()
+			got.Timeout, got.TimeoutSet = timeout.Lookup()
+			got.TimeoutSrc = timeout.Source().String()
+			got.Paths = paths.Value()
+			got.Level = level.Value().String()
+			return nil
+		}
+		args := []string{"prog", "--server-v", "-server-p", "a", "-server-p", "b", "--server-level", "x"}
+		if err := app.Run(args); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+	})
+}
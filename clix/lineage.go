@@ -0,0 +1,149 @@
+package clix
+
+import (
+	"sync"
+
+	"github.com/urfave/cli/v2"
+)
+
+// LineageFlagSet is FlagSet that understands the lineage of the subcommands.
+//
+// The embedded FlagSet has the flags set in the context and all of its
+// ancestors, so that a flag set on the parent command is visible to
+// LineageFlagSet initialized in the Before of the subcommand.
+//
+// The embedded FlagSet is updated by Init, so it can be shared with Var,
+// Group and Constraint.
+type LineageFlagSet struct {
+	FlagSet
+
+	mu      sync.RWMutex
+	lineage []*cli.Context
+	levels  map[string]int
+}
+
+// NewLineageFlagSet returns *LineageFlagSet.
+func NewLineageFlagSet() *LineageFlagSet {
+	return &LineageFlagSet{FlagSet: NewFlagSet()}
+}
+
+// Init initializes fs using the local flag names of c and all of its
+// ancestors. If a flag is set at more than one level, the nearest one to c
+// takes precedence.
+func (fs *LineageFlagSet) Init(c *cli.Context) error {
+	fs.init(c)
+	return nil
+}
+
+// Validate returns a function that calls fs.Init(c), then checks all of
+// constraint against the flags set in c and all of its ancestors, and returns
+// all the violations together as Errors.
+// Validate is intended to be used as cli.BeforeFunc of the subcommand.
+//
+// Unlike the Validate of the embedded FlagSet, the flags set on the parent
+// commands satisfy constraint.
+func (fs *LineageFlagSet) Validate(constraint ...Constraint) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		return CheckConstraints(fs.init(c), constraint...)
+	}
+}
+
+// init is Init that returns FlagSet of the run of c, which is not changed by
+// the other runs.
+func (fs *LineageFlagSet) init(c *cli.Context) FlagSet {
+	var lineage []*cli.Context
+	levels := make(map[string]int)
+	sources := make(FlagSetSnapshot)
+	for _, ctx := range c.Lineage() {
+		// The outermost context is not of any app.
+		if ctx.App == nil {
+			continue
+		}
+		level := len(lineage)
+		lineage = append(lineage, ctx)
+		for _, name := range ctx.LocalFlagNames() {
+			if _, ok := sources[name]; !ok {
+				sources[name] = recordedSource(ctx, name)
+				levels[name] = level
+			}
+		}
+	}
+
+	run := newFlagSet(sources, c)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.FlagSet.Restore(sources)
+	fs.FlagSet.bind(c)
+	fs.lineage, fs.levels = lineage, levels
+	return run
+}
+
+// Level returns the number of the ancestors between the context passed to
+// Init and the context in which flag is set, 0 if flag is set in the context
+// itself, or -1 if flag is not set at any level.
+//
+// The flags set only by EnvVars or FilePath are not set at any level.
+func (fs *LineageFlagSet) Level(flag cli.Flag) int {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.level(flag)
+}
+
+// level is Level without locking.
+func (fs *LineageFlagSet) level(flag cli.Flag) int {
+	level := -1
+	for _, name := range flag.Names() {
+		if v, ok := fs.levels[name]; ok && (level < 0 || v < level) {
+			level = v
+		}
+	}
+	return level
+}
+
+// Context returns the context in which flag is set, or the context passed to
+// Init if flag is not set at any level.
+//
+// The value of the inherited flag should be read from Context(flag), since
+// cli.Context looks up the nearest command that defines the flag even if the
+// flag is set on the ancestor.
+//
+//	e.g.
+//	verbose := fs.Context(flagVerbose).Bool(flagVerbose.Name)
+func (fs *LineageFlagSet) Context(flag cli.Flag) *cli.Context {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	if len(fs.lineage) == 0 {
+		return nil
+	}
+	if level := fs.level(flag); level > 0 {
+		return fs.lineage[level]
+	}
+	return fs.lineage[0]
+}
+
+// Inherit appends flag to the Flags of commands and all of their subcommands,
+// so that flag may be given at any level.
+// The commands that already have flag are skipped.
+//
+// Destination of the inherited flags is reset to the default value each time
+// a subcommand parses its flags, use LineageFlagSet.Context to read the value.
+func Inherit(commands []*cli.Command, flag ...cli.Flag) {
+	walkCommands(commands, nil, func(_ []string, cmd *cli.Command) {
+		for _, f := range flag {
+			if !hasFlag(cmd.Flags, f) {
+				cmd.Flags = append(cmd.Flags, f)
+			}
+		}
+	})
+}
+
+// hasFlag returns true if flags has flag.
+func hasFlag(flags []cli.Flag, flag cli.Flag) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package clix_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLineageFlagSet(t *testing.T) {
+	type result struct {
+		VerboseSet   bool
+		VerboseLevel int
+		Verbose      bool
+		NameSet      bool
+		NameLevel    int
+		Name         string
+		PortLevel    int
+	}
+
+	run := func(args ...string) result {
+		flagVerbose := &cli.BoolFlag{Name: "verbose", Aliases: []string{"v"}}
+		flagName := &cli.StringFlag{Name: "name", Value: "alice"}
+		flagPort := &cli.IntFlag{Name: "port"}
+
+		var got result
+		fs := clix.NewLineageFlagSet()
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{flagVerbose, flagName}
+		app.Commands = []*cli.Command{
+			{
+				Name:  "serve",
+				Flags: []cli.Flag{flagPort},
+				Subcommands: []*cli.Command{
+					{
+						Name:   "run",
+						Before: fs.Init,
+						Action: func(c *cli.Context) error {
+							got = result{
+								VerboseSet:   fs.IsSet(flagVerbose),
+								VerboseLevel: fs.Level(flagVerbose),
+								Verbose:      fs.Context(flagVerbose).Bool(flagVerbose.Name),
+								NameSet:      fs.IsSet(flagName),
+								NameLevel:    fs.Level(flagName),
+								Name:         fs.Context(flagName).String(flagName.Name),
+								PortLevel:    fs.Level(flagPort),
+							}
+							return nil
+						},
+					},
+				},
+			},
+		}
+		clix.Inherit(app.Commands, flagVerbose)
+
+		if err := app.Run(append([]string{"prog"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	cases := []struct {
+		Args []string
+		Want result
+	}{
+		{
+			[]string{"serve", "run"},
+			result{VerboseLevel: -1, NameLevel: -1, Name: "alice", PortLevel: -1},
+		},
+		{
+			[]string{"-v", "--name", "bob", "serve", "run"},
+			result{VerboseSet: true, VerboseLevel: 2, Verbose: true, NameSet: true, NameLevel: 2, Name: "bob", PortLevel: -1},
+		},
+		{
+			[]string{"serve", "--port", "80", "-v", "run"},
+			result{VerboseSet: true, VerboseLevel: 1, Verbose: true, NameLevel: -1, Name: "alice", PortLevel: 1},
+		},
+		{
+			[]string{"serve", "run", "--verbose"},
+			result{VerboseSet: true, VerboseLevel: 0, Verbose: true, NameLevel: -1, Name: "alice", PortLevel: -1},
+		},
+	}
+	for i, c := range cases {
+		got := run(c.Args...)
+		if diff := cmp.Diff(c.Want, got); diff != "" {
+			t.Errorf("[%d] -want +got\n%s", i, diff)
+		}
+	}
+}
+
+func TestLineageFlagSet_Validate(t *testing.T) {
+	run := func(args ...string) error {
+		flagName := &cli.StringFlag{Name: "name"}
+
+		fs := clix.NewLineageFlagSet()
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{flagName}
+		app.Commands = []*cli.Command{
+			{
+				Name:   "run",
+				Before: fs.Validate(clix.RequiresOneOf(flagName)),
+				Action: func(c *cli.Context) error { return nil },
+			},
+		}
+		return app.Run(append([]string{"prog"}, args...))
+	}
+
+	if err := run("--name", "x", "run"); err != nil {
+		t.Errorf("--name x run: %v", err)
+	}
+	if err := run("run"); !errors.Is(err, clix.ErrMissingFlags) {
+		t.Errorf("run: got %v, want %v", err, clix.ErrMissingFlags)
+	}
+}
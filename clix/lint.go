@@ -0,0 +1,219 @@
+package clix
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Problem is a problem of the definition of a flag found by Lint.
+type Problem struct {
+	// Command is the space separated names of the command the flag belongs to,
+	// empty string for the global flags.
+	Command string
+
+	// Flag is the name of the flag.
+	Flag string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String returns the readable representation of p.
+//
+//	e.g.
+//	`command "serve": --port: Usage is empty`
+func (p Problem) String() string {
+	s := prefixFor(p.Flag) + p.Flag + ": " + p.Message
+	if len(p.Command) > 0 {
+		s = fmt.Sprintf("command %q: %s", p.Command, s)
+	}
+	return s
+}
+
+// Lint checks the definitions of the flags of app and all of its
+// subcommands, returns the problems found.
+//
+// The following are reported.
+//
+//   - the names and the aliases shared by the flags of the same command
+//   - the environment variables that are not `<PREFIX><NAME>` of the names
+//     of the flag as NewFlagName, or the naming passed to UseNaming, makes
+//   - the empty Usage
+//   - TakesFile not set on the flags whose names look like paths
+//   - Required flags that have default values
+//   - Destination shared by the different flags
+//
+// Lint is intended to be called from a unit test.
+func Lint(app *cli.App) []Problem {
+	var problems []Problem
+	checked := make(map[cli.Flag]bool)
+	destinations := make(map[uintptr]cli.Flag)
+
+	lint := func(command string, flags []cli.Flag) {
+		report := func(flag cli.Flag, format string, a ...interface{}) {
+			problems = append(problems, Problem{
+				Command: command,
+				Flag:    flag.Names()[0],
+				Message: fmt.Sprintf(format, a...),
+			})
+		}
+
+		names := newCollisionIndex()
+		for _, flag := range flags {
+			if isHelpOrVersionFlag(flag) {
+				continue
+			}
+			for _, name := range flag.Names() {
+				names.add(name, flag)
+			}
+			if checked[flag] {
+				continue
+			}
+			checked[flag] = true
+
+			if msg := lintEnvVars(app, flag); len(msg) > 0 {
+				report(flag, "%s", msg)
+			}
+			if len(flagStringField(flag, "Usage")) == 0 {
+				report(flag, "Usage is empty")
+			}
+			if v := flagField(flag, "TakesFile"); v.IsValid() && !v.Bool() && looksLikePath(flag.Names()[0]) {
+				report(flag, "TakesFile is not set")
+			}
+			if v := flagField(flag, "Required"); v.IsValid() && v.Bool() && hasDefaultValue(flag) {
+				report(flag, "Required flag has the default value %q", defaultValue(flag))
+			}
+			if v := flagField(flag, "Destination"); v.IsValid() && v.Kind() == reflect.Ptr && !v.IsNil() {
+				if other, ok := destinations[v.Pointer()]; ok {
+					report(flag, "Destination is shared with %s", describeFlag(other))
+				} else {
+					destinations[v.Pointer()] = flag
+				}
+			}
+		}
+		names.each(func(name string, flags []cli.Flag) {
+			for _, flag := range flags[1:] {
+				report(flag, "%s%s is also used by %s", prefixFor(name), name, describeFlag(flags[0]))
+			}
+		})
+	}
+
+	lint("", app.Flags)
+	walkCommands(app.Commands, nil, func(path []string, cmd *cli.Command) {
+		lint(strings.Join(path, " "), cmd.Flags)
+	})
+	return problems
+}
+
+// lintEnvVars returns the description of the problem of the environment
+// variables of flag, or an empty string if there is no problem.
+//
+// Each environment variable must be `<PREFIX><NAME>` where PREFIX is shared
+// by all of them and NAME is one of the names of flag in upper snake case.
+// If the naming of the environment variables is passed to UseNaming on app,
+// PREFIX must be its Prefix, and NAME is joined by the separators of its
+// EnvPolicy instead of "_".
+// The group separator is treated as the separator, and the consecutive
+// separators are treated as a single one.
+func lintEnvVars(app *cli.App, flag cli.Flag) string {
+	envVars := flagStringSliceField(flag, "EnvVars")
+	if len(envVars) == 0 {
+		return ""
+	}
+	naming := namingOf(app, envVars[0])
+	sep := naming.Env.Separator
+	if len(sep) == 0 {
+		sep = "_"
+	}
+	groupSep := naming.Env.GroupSeparator
+	if len(groupSep) == 0 {
+		groupSep = sep
+	}
+	normalize := func(s string) string {
+		return squeezeSeparators(strings.ReplaceAll(s, groupSep, sep), sep)
+	}
+
+	var keys []string
+	for _, name := range flag.Names() {
+		keys = append(keys, normalize(strings.ReplaceAll(strings.ToUpper(name), "-", sep)))
+	}
+
+	first := normalize(envVars[0])
+	prefix, ok := "", false
+	for _, k := range keys {
+		p := strings.TrimSuffix(first, k)
+		if p == first {
+			continue
+		}
+		if len(naming.Prefix) > 0 {
+			ok = p == normalize(string(naming.Prefix))
+		} else {
+			ok = p == "" || strings.HasSuffix(p, sep)
+		}
+		if ok {
+			prefix = p
+			break
+		}
+	}
+	if !ok {
+		return fmt.Sprintf("$%s does not match any name of the flag", envVars[0])
+	}
+
+	for _, v := range envVars {
+		if strings.IndexFunc(strings.TrimPrefix(v, string(naming.Prefix)), func(r rune) bool {
+			return !('A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_' ||
+				strings.ContainsRune(sep, r) || strings.ContainsRune(groupSep, r))
+		}) >= 0 {
+			return fmt.Sprintf("$%s is not in upper snake case", v)
+		}
+		matched := false
+		for _, k := range keys {
+			if normalize(v) == prefix+k {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Sprintf("$%s does not match %s<NAME>", v, prefix)
+		}
+	}
+	return ""
+}
+
+// squeezeSeparators replaces the consecutive sep in s with a single sep,
+// so that the group separator of EnvPolicy such as "__" is accepted.
+func squeezeSeparators(s, sep string) string {
+	for strings.Contains(s, sep+sep) {
+		s = strings.ReplaceAll(s, sep+sep, sep)
+	}
+	return s
+}
+
+// pathWords is the words in the name of the flags that look like paths.
+var pathWords = map[string]bool{
+	"file":   true,
+	"path":   true,
+	"dir":    true,
+	"cert":   true,
+	"config": true,
+	"conf":   true,
+}
+
+// looksLikePath returns true if any word of name is in pathWords.
+func looksLikePath(name string) bool {
+	for _, w := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		if pathWords[strings.ToLower(w)] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDefaultValue returns true if the Value of flag is not the zero value.
+func hasDefaultValue(flag cli.Flag) bool {
+	v := flagField(flag, "Value")
+	return v.IsValid() && !v.IsZero() && len(defaultValue(flag)) > 0
+}
@@ -0,0 +1,115 @@
+package clix_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLint(t *testing.T) {
+	prefix := clix.FlagPrefix("APP_")
+	name := func(group, name string) *clix.FlagName {
+		return clix.NewFlagName(prefix, group, name)
+	}
+
+	var port int
+	fnCert := name("", "tls-cert")
+	fnPort := name("serve", "port")
+	fnPath := name("serve", "path")
+	fnUser := name("serve", "user")
+
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:      fnCert.Name,
+			Aliases:   fnCert.Aliases,
+			EnvVars:   fnCert.EnvVars,
+			Usage:     "certificate",
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:    "config",
+			EnvVars: []string{"APP_CONFIG", "APP_CFG"},
+			Usage:   "config file",
+		},
+		&cli.BoolFlag{
+			Name:    "debug",
+			EnvVars: []string{"app-debug"},
+			Usage:   "debug mode",
+		},
+	}
+	app.Commands = []*cli.Command{
+		{
+			Name: "serve",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:        fnPort.Name,
+					Aliases:     fnPort.Aliases,
+					EnvVars:     fnPort.EnvVars,
+					Usage:       "port",
+					Destination: &port,
+					Required:    true,
+					Value:       80,
+				},
+				&cli.StringFlag{
+					Name:      fnPath.Name,
+					Aliases:   fnPath.Aliases,
+					EnvVars:   fnPath.EnvVars,
+					Usage:     "path",
+					TakesFile: true,
+				},
+				&cli.IntFlag{
+					Name:        "backlog",
+					Destination: &port,
+					Usage:       "backlog",
+				},
+				&cli.StringFlag{
+					Name:     fnUser.Name,
+					Aliases:  fnUser.Aliases,
+					EnvVars:  fnUser.EnvVars,
+					Required: true,
+				},
+			},
+		},
+	}
+
+	want := []clix.Problem{
+		{Flag: "config", Message: "$APP_CFG does not match APP_<NAME>"},
+		{Flag: "config", Message: "TakesFile is not set"},
+		{Flag: "debug", Message: "$app-debug does not match any name of the flag"},
+		{Command: "serve", Flag: "serve-port", Message: `Required flag has the default value "80"`},
+		{Command: "serve", Flag: "backlog", Message: "Destination is shared with --serve-port [$APP_SERVE_PORT, $APP_SERVE_P]"},
+		{Command: "serve", Flag: "serve-user", Message: "Usage is empty"},
+		{Command: "serve", Flag: "serve-path", Message: "--serve-p is also used by --serve-port [$APP_SERVE_PORT, $APP_SERVE_P]"},
+	}
+	got := clix.Lint(app)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+
+	wantString := `command "serve": --serve-user: Usage is empty`
+	if diff := cmp.Diff(wantString, got[5].String()); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func TestLint_useNaming(t *testing.T) {
+	naming := clix.Naming{
+		Prefix: clix.FlagPrefix("app."),
+		Env:    clix.EnvPolicy{Separator: ".", GroupSeparator: "/"},
+	}
+	fn := naming.NewFlagName("api-v1", "tls-cert")
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{Name: fn.Name, Aliases: fn.Aliases, EnvVars: fn.EnvVars, Usage: "certificate", TakesFile: true},
+	}
+	if got := clix.Lint(app); len(got) == 0 {
+		t.Fatal("want problems without UseNaming")
+	}
+	clix.UseNaming(app, naming)
+	if got := clix.Lint(app); len(got) > 0 {
+		t.Fatal(got)
+	}
+}
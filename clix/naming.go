@@ -0,0 +1,141 @@
+package clix
+
+import (
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Naming names the flags of Prefix in the same way as NewFlagName and
+// NewFlagNameAlias do, with the options that FlagPrefix does not carry.
+//
+// The zero value of each option keeps the default behavior,
+// i.e. Naming{Prefix: prefix}.NewFlagName(group, name) is equivalent to
+// NewFlagName(prefix, group, name).
+type Naming struct {
+	// Prefix is the prefix of the environment variables.
+	Prefix FlagPrefix
+
+	// Alias is the strategy to make the alias of the flag name,
+	// AliasInitials if nil.
+	Alias AliasStrategy
+
+	// Env is the policy of the names of the environment variables.
+	Env EnvPolicy
+
+	// DefaultFiles is the locations of the default files of the flags.
+	// The default file is used if none of the environment variables
+	// `<PREFIX><NAME>_FILE` is set.
+	DefaultFiles *DefaultFiles
+}
+
+// NewFlagName returns *FlagName.
+// The alias is made by n.Alias.
+func (n Naming) NewFlagName(group, name string) *FlagName {
+	alias := n.Alias
+	if alias == nil {
+		alias = AliasInitials
+	}
+	return n.NewFlagNameAlias(group, name, alias(name))
+}
+
+// NewFlagNameAlias returns *FlagName.
+// If alias is the empty string, the flag has no alias.
+//
+// EnvVars are named by n.Env.
+// FilePath is the file named by the environment variables `<NAME>_FILE`,
+// or the default file of the name if n has DefaultFiles.
+func (n Naming) NewFlagNameAlias(group, name, alias string) *FlagName {
+	policy := n.Env
+
+	keys := []string{policy.key(group, name)}
+	if len(group) > 0 {
+		name = group + "-" + name
+	}
+
+	var aliases []string
+	if len(alias) > 0 {
+		if !policy.LongNameOnly {
+			keys = append(keys, policy.key(group, alias))
+		}
+		if len(group) > 0 {
+			alias = group + "-" + alias
+		}
+		aliases = []string{alias}
+	}
+
+	filePath := n.FilePath(keys...)
+	if len(filePath) == 0 && n.DefaultFiles != nil {
+		filePath = n.DefaultFiles.FilePath(name)
+	}
+
+	return &FlagName{
+		Name:     name,
+		Aliases:  aliases,
+		EnvVars:  n.Prefix.EnvVars(keys...),
+		FilePath: filePath,
+	}
+}
+
+// NewFlagNameDefault returns n.NewFlagNameAlias(group, name, alias) if
+// n.Alias is nil, otherwise n.NewFlagName(group, name).
+// It is intended for the packages that give their own aliases to the flags,
+// so that the strategy of the user takes precedence.
+func (n Naming) NewFlagNameDefault(group, name, alias string) *FlagName {
+	if n.Alias == nil {
+		return n.NewFlagNameAlias(group, name, alias)
+	}
+	return n.NewFlagName(group, name)
+}
+
+// NewGroup returns *Group whose flags are named by n.
+func (n Naming) NewGroup(name string) *Group {
+	return &Group{
+		Prefix:  n.Prefix,
+		Name:    name,
+		FlagSet: NewFlagSet(),
+		naming:  n,
+	}
+}
+
+// FilePath returns n.Prefix.FilePath(s...),
+// or an empty string if n.Env.NoFile is true.
+func (n Naming) FilePath(s ...string) string {
+	if n.Env.NoFile {
+		return ""
+	}
+	return n.Prefix.FilePath(s...)
+}
+
+// metadataNamings is the key of cli.App.Metadata where the namings passed to
+// UseNaming are kept.
+const metadataNamings = "github.com/takumakei/go-urfave-cli/clix.namings"
+
+// UseNaming tells app the namings of its flags, so that the commands and the
+// checks of clix such as PrintConfig and CheckCollisions follow them.
+// UseNaming is intended to be called at the construction of app.
+func UseNaming(app *cli.App, naming ...Naming) {
+	if app.Metadata == nil {
+		app.Metadata = make(map[string]interface{})
+	}
+	list, _ := app.Metadata[metadataNamings].([]Naming)
+	app.Metadata[metadataNamings] = append(list, naming...)
+}
+
+// namingOf returns the naming passed to UseNaming on app whose Prefix is the
+// longest one that env starts with, or Naming{} if there is no such naming.
+// app may be nil.
+func namingOf(app *cli.App, env string) Naming {
+	var found Naming
+	if app == nil {
+		return found
+	}
+	list, _ := app.Metadata[metadataNamings].([]Naming)
+	longest := -1
+	for _, n := range list {
+		if fp := string(n.Prefix); len(fp) > longest && strings.HasPrefix(env, fp) {
+			found, longest = n, len(fp)
+		}
+	}
+	return found
+}
@@ -0,0 +1,361 @@
+package clix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// EnablePrintConfigCommand appends PrintConfigCommand to app.Commands.
+func EnablePrintConfigCommand(app *cli.App) {
+	app.Commands = append(app.Commands, PrintConfigCommand)
+}
+
+// PrintConfigCommand is the command to print the effective configuration of
+// the flags of the app and all of its subcommands.
+var PrintConfigCommand = &cli.Command{
+	Name:      "print-config",
+	Usage:     "print the effective configuration",
+	ArgsUsage: " ",
+	Action:    PrintConfig,
+	Flags: []cli.Flag{
+		FlagPrintConfigFormat,
+	},
+}
+
+// FlagPrintConfigFormat is the flag of PrintConfigCommand.
+var FlagPrintConfigFormat = &cli.StringFlag{
+	Name:    "format",
+	Aliases: []string{"f"},
+	Usage:   "output `format` [table|json|env]",
+	Value:   "table",
+}
+
+// PrintConfig writes the effective configuration to c.App.Writer in the
+// format of FlagPrintConfigFormat.
+func PrintConfig(c *cli.Context) error {
+	entries := ConfigEntries(c)
+	switch format := c.String(FlagPrintConfigFormat.Name); format {
+	case "table":
+		return WriteConfigTable(c.App.Writer, entries)
+	case "json":
+		return WriteConfigJSON(c.App.Writer, entries)
+	case "env":
+		return WriteConfigEnv(c.App.Writer, entries)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// ConfigEntry is the effective configuration of a flag.
+type ConfigEntry struct {
+	// Command is the space separated names of the command the flag belongs to,
+	// empty string for the global flags.
+	Command string `json:"command,omitempty"`
+
+	// Flag is the name of the flag.
+	Flag string `json:"flag"`
+
+	// Value is the effective value of the flag, redacted if the flag is secret.
+	Value string `json:"value"`
+
+	// Source is where the value came from.
+	Source string `json:"source"`
+
+	// EnvVars is the environment variables of the flag.
+	EnvVars []string `json:"env_vars,omitempty"`
+
+	// FileEnvVars is the environment variables of the path of the file
+	// that FlagPrefix.FilePath looks up.
+	FileEnvVars []string `json:"file_env_vars,omitempty"`
+
+	// Secret is true if the flag is secret.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// Redacted is the value of the secret flags in ConfigEntry.
+const Redacted = "<redacted>"
+
+// metadataSecrets is the key of cli.App.Metadata where the flags marked by
+// MarkSecret are kept.
+const metadataSecrets = "github.com/takumakei/go-urfave-cli/clix.secrets"
+
+// MarkSecret marks the flags of app as secret so that their values are
+// redacted. MarkSecret is intended to be called at the construction of app.
+func MarkSecret(app *cli.App, flag ...cli.Flag) {
+	if app.Metadata == nil {
+		app.Metadata = make(map[string]interface{})
+	}
+	secrets, ok := app.Metadata[metadataSecrets].(map[cli.Flag]struct{})
+	if !ok {
+		secrets = make(map[cli.Flag]struct{})
+		app.Metadata[metadataSecrets] = secrets
+	}
+	for _, f := range flag {
+		secrets[f] = struct{}{}
+	}
+}
+
+// IsSecret returns true if flag is marked by MarkSecret on app,
+// or flag or its Value has the method `IsSecret() bool` that returns true.
+// app may be nil.
+func IsSecret(app *cli.App, flag cli.Flag) bool {
+	if app != nil {
+		if secrets, ok := app.Metadata[metadataSecrets].(map[cli.Flag]struct{}); ok {
+			if _, ok := secrets[flag]; ok {
+				return true
+			}
+		}
+	}
+	if s, ok := flag.(interface{ IsSecret() bool }); ok {
+		return s.IsSecret()
+	}
+	if v := flagField(flag, "Value"); v.IsValid() && v.CanInterface() {
+		if s, ok := v.Interface().(interface{ IsSecret() bool }); ok {
+			return s.IsSecret()
+		}
+	}
+	return false
+}
+
+// ConfigEntries returns the effective configuration of the flags of the root
+// app of c and all of its subcommands.
+//
+// The values of the flags of the commands in c.Lineage() are the parsed ones,
+// the others are looked up in the environment variables and FilePath.
+func ConfigEntries(c *cli.Context) []ConfigEntry {
+	root := rootApp(c)
+	parsed := make(map[cli.Flag]*cli.Context)
+	for _, ctx := range c.Lineage() {
+		// The outermost context is not of any app.
+		if ctx.App == nil {
+			continue
+		}
+		for _, flag := range localFlags(ctx) {
+			if _, ok := parsed[flag]; !ok {
+				parsed[flag] = ctx
+			}
+		}
+	}
+
+	var entries []ConfigEntry
+	add := func(command string, flags []cli.Flag) {
+		for _, flag := range flags {
+			if isHelpOrVersionFlag(flag) {
+				continue
+			}
+			entries = append(entries, configEntry(root, command, flag, parsed[flag], c))
+		}
+	}
+
+	add("", root.Flags)
+	walkCommands(root.Commands, nil, func(path []string, cmd *cli.Command) {
+		add(strings.Join(path, " "), cmd.Flags)
+	})
+	return entries
+}
+
+// rootApp returns the outermost app in c.Lineage().
+func rootApp(c *cli.Context) *cli.App {
+	var root *cli.App
+	for _, ctx := range c.Lineage() {
+		if ctx.App != nil {
+			root = ctx.App
+		}
+	}
+	return root
+}
+
+// walkCommands calls fn for each command in commands and their subcommands
+// in depth-first order.
+func walkCommands(commands []*cli.Command, path []string, fn func([]string, *cli.Command)) {
+	for _, cmd := range commands {
+		p := append(append([]string(nil), path...), cmd.Name)
+		fn(p, cmd)
+		walkCommands(cmd.Subcommands, p, fn)
+	}
+}
+
+// isHelpOrVersionFlag returns true if flag is cli.HelpFlag or cli.VersionFlag.
+func isHelpOrVersionFlag(flag cli.Flag) bool {
+	return flag == cli.HelpFlag || flag == cli.VersionFlag
+}
+
+// configEntry returns ConfigEntry of flag of app.
+// ctx is the context in which flag is parsed, may be nil.
+// c is the context of the run, in which the sources of flag are looked up if
+// ctx is nil.
+func configEntry(app *cli.App, command string, flag cli.Flag, ctx, c *cli.Context) ConfigEntry {
+	envVars := flagStringSliceField(flag, "EnvVars")
+	fileEnvVars := fileEnvVarsOf(app, envVars)
+
+	var value string
+	var src Source
+	if ctx != nil {
+		src = newRunFlagSet(ctx).Source(flag)
+		value = contextValue(ctx, flag)
+	} else {
+		src = envOrFileSource(c, flag)
+		value = sourceValue(c, flag, src)
+	}
+
+	secret := IsSecret(app, flag)
+	if secret && (len(value) > 0 || src.Kind != SourceDefault) {
+		value = Redacted
+	}
+
+	return ConfigEntry{
+		Command:     command,
+		Flag:        flag.Names()[0],
+		Value:       value,
+		Source:      src.String(),
+		EnvVars:     envVars,
+		FileEnvVars: fileEnvVars,
+		Secret:      secret,
+	}
+}
+
+// fileEnvVarsOf returns the environment variables `<ENV>_FILE` of envVars
+// that FlagPrefix.FilePath looks up, skipping the ones whose naming passed to
+// UseNaming on app has EnvPolicy.NoFile.
+func fileEnvVarsOf(app *cli.App, envVars []string) []string {
+	var list []string
+	for _, v := range envVars {
+		if !namingOf(app, v).Env.NoFile {
+			list = append(list, v+"_FILE")
+		}
+	}
+	return list
+}
+
+// contextValue returns the value of flag parsed in ctx.
+func contextValue(ctx *cli.Context, flag cli.Flag) string {
+	name := flag.Names()[0]
+	switch flag.(type) {
+	case *cli.StringSliceFlag:
+		return strings.Join(ctx.StringSlice(name), ",")
+	case *cli.IntSliceFlag:
+		return joinValues(ctx.IntSlice(name))
+	case *cli.Int64SliceFlag:
+		return joinValues(ctx.Int64Slice(name))
+	case *cli.Float64SliceFlag:
+		return joinValues(ctx.Float64Slice(name))
+	}
+	// ctx.String returns the string representation of any type of flags.
+	return ctx.String(name)
+}
+
+// sourceValue returns the value of flag that is not parsed in the run of c.
+func sourceValue(c *cli.Context, flag cli.Flag, src Source) string {
+	switch src.Kind {
+	case SourceEnv:
+		return os.Getenv(src.Name)
+	case SourceDotEnv:
+		v, _ := dotEnvLookupOf(c).find(flagStringSliceField(flag, "EnvVars"))
+		return v.value
+	case SourceFile:
+		p, _ := os.ReadFile(src.Name)
+		return strings.TrimRight(string(p), "\r\n")
+	}
+	return defaultValue(flag)
+}
+
+// defaultValue returns the string representation of the Value of flag.
+func defaultValue(flag cli.Flag) string {
+	v := flagField(flag, "Value")
+	if !v.IsValid() {
+		return ""
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return ""
+	}
+	switch v := v.Interface().(type) {
+	case *cli.StringSlice:
+		return strings.Join(v.Value(), ",")
+	case *cli.IntSlice:
+		return joinValues(v.Value())
+	case *cli.Int64Slice:
+		return joinValues(v.Value())
+	case *cli.Float64Slice:
+		return joinValues(v.Value())
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// joinValues returns the elements of a slice joined with ",".
+func joinValues(slice interface{}) string {
+	v := reflect.ValueOf(slice)
+	list := make([]string, v.Len())
+	for i := range list {
+		list[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(list, ",")
+}
+
+// WriteConfigTable writes entries to w in the table format.
+func WriteConfigTable(w io.Writer, entries []ConfigEntry) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMMAND\tFLAG\tVALUE\tSOURCE\tENV\tFILE ENV")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Command,
+			prefixFor(e.Flag)+e.Flag,
+			e.Value,
+			e.Source,
+			strings.Join(e.EnvVars, ","),
+			strings.Join(e.FileEnvVars, ","),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// trims the padding of the empty columns at the end of lines.
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		if _, err := io.WriteString(w, strings.TrimRight(line, " \n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteConfigJSON writes entries to w in the JSON format.
+func WriteConfigJSON(w io.Writer, entries []ConfigEntry) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteConfigEnv writes entries to w in the env-file format.
+// The entries without the environment variables are skipped,
+// the entries of the default values are commented out.
+func WriteConfigEnv(w io.Writer, entries []ConfigEntry) error {
+	for _, e := range entries {
+		if len(e.EnvVars) == 0 {
+			continue
+		}
+		comment := ""
+		if e.Source == SourceDefault.String() {
+			comment = "# "
+		}
+		if _, err := fmt.Fprintf(w, "%s%s=%s\n", comment, e.EnvVars[0], strconv.Quote(e.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package clix_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func testPrintConfigApp(password string) *cli.App {
+	prefix := clix.FlagPrefix("CLIX_TEST_PRINT_")
+	name := clix.NewFlagName(prefix, "", "name")
+	pass := clix.NewFlagName(prefix, "", "password")
+	port := clix.NewFlagName(prefix, "serve", "port")
+
+	flagPassword := &cli.StringFlag{
+		Name:     pass.Name,
+		Aliases:  pass.Aliases,
+		EnvVars:  pass.EnvVars,
+		FilePath: password,
+	}
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    name.Name,
+			Aliases: name.Aliases,
+			EnvVars: name.EnvVars,
+			Value:   "alice",
+		},
+		flagPassword,
+	}
+	app.Commands = []*cli.Command{
+		{
+			Name: "serve",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    port.Name,
+					Aliases: port.Aliases,
+					EnvVars: port.EnvVars,
+					Value:   80,
+				},
+				&cli.StringSliceFlag{
+					Name:  "tag",
+					Value: cli.NewStringSlice("a", "b"),
+				},
+			},
+		},
+	}
+	clix.MarkSecret(app, flagPassword)
+	clix.EnablePrintConfigCommand(app)
+	return app
+}
+
+func TestPrintConfig(t *testing.T) {
+	password := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(password, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("CLIX_TEST_PRINT_SERVE_PORT", "8080")
+	t.Cleanup(func() { os.Unsetenv("CLIX_TEST_PRINT_SERVE_PORT") })
+
+	t.Run("json", func(t *testing.T) {
+		var s strings.Builder
+		app := testPrintConfigApp(password)
+		app.Writer = &s
+		if err := app.Run([]string{"prog", "--name", "bob", "print-config", "--format", "json"}); err != nil {
+			t.Fatal(err)
+		}
+		want := `[
+  {
+    "flag": "name",
+    "value": "bob",
+    "source": "arg:--name",
+    "env_vars": [
+      "CLIX_TEST_PRINT_NAME",
+      "CLIX_TEST_PRINT_N"
+    ],
+    "file_env_vars": [
+      "CLIX_TEST_PRINT_NAME_FILE",
+      "CLIX_TEST_PRINT_N_FILE"
+    ]
+  },
+  {
+    "flag": "password",
+    "value": "<redacted>",
+    "source": "file:` + password + `",
+    "env_vars": [
+      "CLIX_TEST_PRINT_PASSWORD",
+      "CLIX_TEST_PRINT_P"
+    ],
+    "file_env_vars": [
+      "CLIX_TEST_PRINT_PASSWORD_FILE",
+      "CLIX_TEST_PRINT_P_FILE"
+    ],
+    "secret": true
+  },
+  {
+    "command": "serve",
+    "flag": "serve-port",
+    "value": "8080",
+    "source": "env:CLIX_TEST_PRINT_SERVE_PORT",
+    "env_vars": [
+      "CLIX_TEST_PRINT_SERVE_PORT",
+      "CLIX_TEST_PRINT_SERVE_P"
+    ],
+    "file_env_vars": [
+      "CLIX_TEST_PRINT_SERVE_PORT_FILE",
+      "CLIX_TEST_PRINT_SERVE_P_FILE"
+    ]
+  },
+  {
+    "command": "serve",
+    "flag": "tag",
+    "value": "a,b",
+    "source": "default"
+  },
+  {
+    "command": "print-config",
+    "flag": "format",
+    "value": "json",
+    "source": "arg:--format"
+  }
+]
+`
+		if diff := cmp.Diff(want, s.String()); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		var s strings.Builder
+		app := testPrintConfigApp(password)
+		app.Writer = &s
+		if err := app.Run([]string{"prog", "print-config", "--format", "env"}); err != nil {
+			t.Fatal(err)
+		}
+		want := `# CLIX_TEST_PRINT_NAME="alice"
+CLIX_TEST_PRINT_PASSWORD="<redacted>"
+CLIX_TEST_PRINT_SERVE_PORT="8080"
+`
+		if diff := cmp.Diff(want, s.String()); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		var s strings.Builder
+		app := testPrintConfigApp("")
+		app.Writer = &s
+		if err := app.Run([]string{"prog", "print-config"}); err != nil {
+			t.Fatal(err)
+		}
+		want := `COMMAND       FLAG          VALUE  SOURCE                          ENV                                                 FILE ENV
+              --name        alice  default                         CLIX_TEST_PRINT_NAME,CLIX_TEST_PRINT_N              CLIX_TEST_PRINT_NAME_FILE,CLIX_TEST_PRINT_N_FILE
+              --password           default                         CLIX_TEST_PRINT_PASSWORD,CLIX_TEST_PRINT_P          CLIX_TEST_PRINT_PASSWORD_FILE,CLIX_TEST_PRINT_P_FILE
+serve         --serve-port  8080   env:CLIX_TEST_PRINT_SERVE_PORT  CLIX_TEST_PRINT_SERVE_PORT,CLIX_TEST_PRINT_SERVE_P  CLIX_TEST_PRINT_SERVE_PORT_FILE,CLIX_TEST_PRINT_SERVE_P_FILE
+serve         --tag         a,b    default
+print-config  --format      table  default
+`
+		if diff := cmp.Diff(want, s.String()); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+	})
+}
+
+func TestMarkSecret(t *testing.T) {
+	flag := &cli.StringFlag{Name: "password"}
+	app := cli.NewApp()
+	clix.MarkSecret(app, flag)
+	if !clix.IsSecret(app, flag) {
+		t.Error("IsSecret(app, flag)=false")
+	}
+	// the mark is of app, not of the flag.
+	if clix.IsSecret(cli.NewApp(), flag) {
+		t.Error("IsSecret(other, flag)=true")
+	}
+}
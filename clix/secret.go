@@ -0,0 +1,171 @@
+package clix
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Secret is cli.Generic for passwords and tokens.
+//
+// The value given to Set is interpreted as follows.
+//
+//	"@path"    the content of the file
+//	"-"        the content of the standard input
+//	"env:NAME" the value of the environment variable NAME
+//	otherwise  the value itself
+//
+// The trailing newlines of the content of the file and the standard input
+// are trimmed, the others are kept as they are. Note that cli reads the file
+// of FilePath of the flag by itself and passes its content to Set as a value,
+// so the content is trimmed only if FilePath of Secret is the same as the
+// flag, which NewSecretFlag does.
+//
+// String always returns an empty string, so that the value is never shown
+// in the help, the error messages of cli, nor PrintConfig.
+// Use Value or Bytes to get the value.
+//
+//	e.g.
+//	var password clix.Secret
+//	flag := clix.NewSecretFlag(clix.NewFlagName(prefix, "", "password"), "password", &password)
+type Secret struct {
+	// Stdin is the standard input read by "-", os.Stdin if nil.
+	Stdin io.Reader
+
+	// FilePath is FilePath of the flag. The value that is the content of the
+	// file is trimmed as well as "@path".
+	FilePath string
+
+	buf []byte
+	set bool
+}
+
+// Set sets the value of s, zeroing the previous one.
+//
+// The error never contains the value.
+func (s *Secret) Set(value string) error {
+	if value == s.Serialize() {
+		// cli copies the value to the aliases of the flag, see Serialize.
+		return nil
+	}
+	var buf []byte
+	switch {
+	case value == "-":
+		r := s.Stdin
+		if r == nil {
+			r = os.Stdin
+		}
+		p, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("secret: reading stdin: %w", err)
+		}
+		buf = bytes.TrimRight(p, "\r\n")
+	case strings.HasPrefix(value, "@"):
+		p, err := os.ReadFile(value[1:])
+		if err != nil {
+			return fmt.Errorf("secret: %w", err)
+		}
+		buf = bytes.TrimRight(p, "\r\n")
+	case strings.HasPrefix(value, "env:"):
+		name := value[len("env:"):]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return fmt.Errorf("secret: environment variable %s is not set", name)
+		}
+		buf = []byte(v)
+	default:
+		buf = []byte(value)
+		if s.fromFile(value) {
+			buf = bytes.TrimRight(buf, "\r\n")
+		}
+	}
+	s.Zero()
+	s.buf = buf
+	s.set = true
+	return nil
+}
+
+// fromFile returns true if value is the content of the file of FilePath that
+// cli reads, the first one readable.
+func (s *Secret) fromFile(value string) bool {
+	if len(s.FilePath) == 0 {
+		return false
+	}
+	for _, path := range strings.Split(s.FilePath, ",") {
+		if p, err := os.ReadFile(path); err == nil {
+			defer zero(p)
+			return string(p) == value
+		}
+	}
+	return false
+}
+
+// String returns an empty string.
+func (s *Secret) String() string {
+	return ""
+}
+
+// Serialize returns the token that Set ignores instead of the value.
+//
+// After parsing, cli copies the value of the flag to its aliases by calling
+// Set of the same *Secret with Serialize(), or String() if Value does not
+// implement cli.Serializer. Since String returns an empty string to hide the
+// value, without Serialize the copy would replace the value with an empty
+// string. The token is the address of s, so
+// it is never the same as the value given by the user.
+func (s *Secret) Serialize() string {
+	return fmt.Sprintf("\x00clix.Secret:%p", s)
+}
+
+// IsSecret returns true.
+func (s *Secret) IsSecret() bool {
+	return true
+}
+
+// IsSet returns true if Set succeeded.
+func (s *Secret) IsSet() bool {
+	return s.set
+}
+
+// Value returns a copy of the value.
+func (s *Secret) Value() string {
+	return string(s.buf)
+}
+
+// Bytes returns the value without copying.
+// The returned slice is zeroed by Zero.
+func (s *Secret) Bytes() []byte {
+	return s.buf
+}
+
+// Zero overwrites the value with zeros and forgets it.
+func (s *Secret) Zero() {
+	zero(s.buf)
+	s.buf = nil
+	s.set = false
+}
+
+// zero overwrites p with zeros.
+func zero(p []byte) {
+	for i := range p {
+		p[i] = 0
+	}
+}
+
+// NewSecretFlag returns *cli.GenericFlag of s named by name.
+// It sets FilePath of s, so that the content of the file is trimmed.
+func NewSecretFlag(name *FlagName, usage string, s *Secret) *cli.GenericFlag {
+	s.FilePath = name.FilePath
+	return &cli.GenericFlag{
+		Name:     name.Name,
+		Aliases:  name.Aliases,
+		Usage:    usage,
+		EnvVars:  name.EnvVars,
+		FilePath: name.FilePath,
+		Value:    s,
+	}
+}
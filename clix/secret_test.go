@@ -0,0 +1,132 @@
+package clix_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestSecret_Set(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(file, []byte("from-file\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLIX_TEST_SECRET", "from-env")
+
+	cases := []struct {
+		Value string
+		Want  string
+	}{
+		{"literal", "literal"},
+		{"literal\r\n", "literal\r\n"},
+		{"@" + file, "from-file"},
+		{"-", "from-stdin"},
+		{"env:CLIX_TEST_SECRET", "from-env"},
+	}
+	for i, c := range cases {
+		s := clix.Secret{Stdin: strings.NewReader("from-stdin\n")}
+		if err := s.Set(c.Value); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+		if got := s.Value(); got != c.Want {
+			t.Errorf("[%d] got %q, want %q", i, got, c.Want)
+		}
+		if got := s.String(); got != "" {
+			t.Errorf("[%d] String()=%q", i, got)
+		}
+	}
+
+	t.Run("error", func(t *testing.T) {
+		var s clix.Secret
+		if err := s.Set("env:CLIX_TEST_SECRET_NOT_SET"); err == nil {
+			t.Error("no error for unset environment variable")
+		}
+		if err := s.Set("@" + file + ".none"); err == nil {
+			t.Error("no error for missing file")
+		}
+		if s.IsSet() {
+			t.Error("IsSet after error")
+		}
+	})
+
+	t.Run("Zero", func(t *testing.T) {
+		var s clix.Secret
+		if err := s.Set("literal"); err != nil {
+			t.Fatal(err)
+		}
+		buf := s.Bytes()
+		s.Zero()
+		if diff := cmp.Diff(make([]byte, len("literal")), buf); diff != "" {
+			t.Fatalf("-want +got\n%s", diff)
+		}
+		if s.IsSet() || s.Value() != "" {
+			t.Error("value remains after Zero")
+		}
+	})
+}
+
+func TestSecret_flag(t *testing.T) {
+	password := new(clix.Secret)
+	if err := password.Set("default-password"); err != nil {
+		t.Fatal(err)
+	}
+	flag := &cli.GenericFlag{Name: "password", Aliases: []string{"p"}, Value: password, Usage: "password"}
+	if !clix.IsSecret(nil, flag) {
+		t.Error("IsSecret(nil, flag)=false")
+	}
+
+	var out strings.Builder
+	var got, str string
+	app := cli.NewApp()
+	app.Writer = &out
+	app.Flags = []cli.Flag{flag}
+	app.Action = func(c *cli.Context) error {
+		got, str = password.Value(), c.String("password")
+		return nil
+	}
+
+	if err := app.Run([]string{"prog", "--help"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "default-password") {
+		t.Errorf("help shows the secret\n%s", out.String())
+	}
+
+	if err := app.Run([]string{"prog", "--password", "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"hunter2", ""}, []string{got, str}); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func TestNewSecretFlag(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		password := new(clix.Secret)
+		name := &clix.FlagName{Name: "password", FilePath: file}
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{clix.NewSecretFlag(name, "password", password)}
+		app.Action = func(*cli.Context) error { return nil }
+		if err := app.Run(append([]string{"prog"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		return password.Value()
+	}
+
+	if got := run(); got != "from-file" {
+		t.Errorf("FilePath: got %q", got)
+	}
+	if got := run("--password", "literal\n"); got != "literal\n" {
+		t.Errorf("literal: got %q", got)
+	}
+}
@@ -0,0 +1,233 @@
+package clix
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/urfave/cli/v2"
+)
+
+// SourceKind represents the kind of the source of the value of a flag.
+type SourceKind int
+
+const (
+	// SourceDefault means the flag is not set, the value is the default.
+	SourceDefault SourceKind = iota
+
+	// SourceArg means the flag is set by the command line arguments.
+	SourceArg
+
+	// SourceEnv means the flag is set by the environment variable.
+	SourceEnv
+
+	// SourceFile means the flag is set by the file of FilePath.
+	SourceFile
+
+	// SourceConfig means the flag is set by the config file.
+	SourceConfig
+
+	// SourceDotEnv means the flag is set by the environment variable loaded
+	// from the dotenv file by DotEnv.
+	SourceDotEnv
+)
+
+// String returns the name of k.
+func (k SourceKind) String() string {
+	switch k {
+	case SourceDefault:
+		return "default"
+	case SourceArg:
+		return "arg"
+	case SourceEnv:
+		return "env"
+	case SourceFile:
+		return "file"
+	case SourceConfig:
+		return "config"
+	case SourceDotEnv:
+		return "dotenv"
+	}
+	return "unknown"
+}
+
+// Source represents where the value of a flag came from.
+type Source struct {
+	// Kind is the kind of the source.
+	Kind SourceKind
+
+	// Name is the name of the source.
+	//
+	//   SourceDefault: empty string
+	//   SourceArg:     the name of the flag
+	//   SourceEnv:     the name of the environment variable
+	//   SourceFile:    the path of the file
+	//   SourceConfig:  the path of the config file
+	//   SourceDotEnv:  the path of the dotenv file
+	Name string
+}
+
+// String returns the readable representation of s.
+//
+//	e.g.
+//	"default"
+//	"arg:--server-address"
+//	"env:EXAMPLE_SERVER_ADDRESS"
+//	"file:/run/secrets/password"
+//	"config:/etc/example/config.yaml"
+//	"dotenv:.env"
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceDefault:
+		return s.Kind.String()
+	case SourceArg:
+		return s.Kind.String() + ":" + prefixFor(s.Name) + s.Name
+	}
+	return s.Kind.String() + ":" + s.Name
+}
+
+// prefixFor returns "-" if name is a single character or "--" otherwise,
+// as same as cli does.
+func prefixFor(name string) string {
+	if len(name) == 1 {
+		return "-"
+	}
+	return "--"
+}
+
+// Source returns where the value of flag came from.
+//
+// The command line arguments take precedence over the environment variables,
+// and the environment variables take precedence over the file of FilePath,
+// in the same order as cli looks them up.
+// The flags set by clix, such as ConfigFile, are reported as recorded.
+func (fs FlagSet) Source(flag cli.Flag) Source {
+	for _, name := range flag.Names() {
+		if s, ok := fs.lookup(name); ok {
+			return s
+		}
+	}
+	return envOrFileSource(fs.context(), flag)
+}
+
+// sourcesKey is the key of the context.Context of c, where the sources of
+// the flags set by clix in c are recorded.
+//
+// The record is kept in the context of the run rather than cli.App, so that
+// it is dropped with the run, and the runs of the same app in parallel do
+// not share it.
+type sourcesKey struct {
+	c *cli.Context
+}
+
+// contextMu guards c.Context of cli.Context replaced by clix, and the
+// records of the sources in it, since the hooks of a context may run in
+// multiple goroutines, e.g. by Parallel.
+var contextMu sync.Mutex
+
+// loadContext returns the value for key in c.Context, or nil.
+// c may be nil.
+func loadContext(c *cli.Context, key interface{}) interface{} {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	if c == nil || c.Context == nil {
+		return nil
+	}
+	return c.Context.Value(key)
+}
+
+// storeContext replaces c.Context with the context that has val for key.
+func storeContext(c *cli.Context, key, val interface{}) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	if c.Context == nil {
+		c.Context = context.Background()
+	}
+	c.Context = context.WithValue(c.Context, key, val)
+}
+
+// recordSource records src as the source of flag set in c.
+func recordSource(c *cli.Context, flag cli.Flag, src Source) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	if c.Context == nil {
+		c.Context = context.Background()
+	}
+	m, ok := c.Context.Value(sourcesKey{c}).(map[string]Source)
+	if !ok {
+		m = make(map[string]Source)
+		c.Context = context.WithValue(c.Context, sourcesKey{c}, m)
+	}
+	for _, name := range flag.Names() {
+		m[name] = src
+	}
+}
+
+// recordedSource returns the source of the flag named name in c recorded by
+// recordSource, or SourceArg if there is no record.
+func recordedSource(c *cli.Context, name string) Source {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	if c.Context != nil {
+		if m, ok := c.Context.Value(sourcesKey{c}).(map[string]Source); ok {
+			if s, ok := m[name]; ok {
+				return s
+			}
+		}
+	}
+	return Source{Kind: SourceArg, Name: name}
+}
+
+// envOrFileSource returns the source of flag in the same way as cli looks up
+// the environment variables and FilePath, and the variables loaded by DotEnv
+// in the run of c between them. c may be nil.
+func envOrFileSource(c *cli.Context, flag cli.Flag) Source {
+	envVars := flagStringSliceField(flag, "EnvVars")
+	for _, env := range envVars {
+		env = strings.TrimSpace(env)
+		if _, ok := os.LookupEnv(env); ok {
+			return Source{Kind: SourceEnv, Name: env}
+		}
+	}
+	if v, ok := dotEnvLookupOf(c).find(envVars); ok {
+		return Source{Kind: SourceDotEnv, Name: v.path}
+	}
+	if filePath := flagStringField(flag, "FilePath"); len(filePath) > 0 {
+		for _, file := range strings.Split(filePath, ",") {
+			if _, err := os.ReadFile(file); err == nil {
+				return Source{Kind: SourceFile, Name: file}
+			}
+		}
+	}
+	return Source{Kind: SourceDefault}
+}
+
+// flagField returns the field of the struct that flag points to,
+// or an invalid reflect.Value if there is no such field.
+func flagField(flag cli.Flag, name string) reflect.Value {
+	v := reflect.Indirect(reflect.ValueOf(flag))
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.FieldByName(name)
+}
+
+// flagStringField returns the string field of flag.
+func flagStringField(flag cli.Flag, name string) string {
+	if v := flagField(flag, name); v.IsValid() && v.Kind() == reflect.String {
+		return v.String()
+	}
+	return ""
+}
+
+// flagStringSliceField returns the []string field of flag.
+func flagStringSliceField(flag cli.Flag, name string) []string {
+	if v := flagField(flag, name); v.IsValid() {
+		if s, ok := v.Interface().([]string); ok {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package clix_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestFlagSet_Source(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(file, []byte("file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Env  string
+		File string
+		Args []string
+		Want string
+	}{
+		{"", "", nil, "default"},
+		{"", file, nil, "file:" + file},
+		{"env", file, nil, "env:CLIX_TEST_SOURCE"},
+		{"env", file, []string{"--password", "arg"}, "arg:--password"},
+		{"", "", []string{"-p", "arg"}, "arg:--password"},
+	}
+
+	for i, c := range cases {
+		if len(c.Env) > 0 {
+			os.Setenv("CLIX_TEST_SOURCE", c.Env)
+		} else {
+			os.Unsetenv("CLIX_TEST_SOURCE")
+		}
+		t.Cleanup(func() { os.Unsetenv("CLIX_TEST_SOURCE") })
+
+		fs := clix.NewFlagSet()
+		flag := &cli.StringFlag{
+			Name:     "password",
+			Aliases:  []string{"p"},
+			EnvVars:  []string{"CLIX_TEST_SOURCE"},
+			FilePath: c.File,
+		}
+		var got string
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{flag}
+		app.Before = fs.Init
+		app.Action = func(c *cli.Context) error {
+			got = fs.Source(flag).String()
+			return nil
+		}
+		if err := app.Run(append([]string{"prog"}, c.Args...)); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(c.Want, got); diff != "" {
+			t.Errorf("[%d] -want +got\n%s", i, diff)
+		}
+	}
+}
+
+func TestFlagSet_IsSet_generic(t *testing.T) {
+	os.Setenv("CLIX_TEST_SOURCE_GENERIC", "x")
+	t.Cleanup(func() { os.Unsetenv("CLIX_TEST_SOURCE_GENERIC") })
+
+	fs := clix.NewFlagSet()
+	level := clix.NewGenericVar[testLevel](&cli.GenericFlag{
+		Name:    "level",
+		EnvVars: []string{"CLIX_TEST_SOURCE_GENERIC"},
+		Value:   new(testLevel),
+	}, fs)
+
+	var got clix.Source
+	var isSet bool
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{level.Flag}
+	app.Before = fs.Init
+	app.Action = func(c *cli.Context) error {
+		got, isSet = level.Source(), level.IsSet()
+		return nil
+	}
+	if err := app.Run([]string{"prog"}); err != nil {
+		t.Fatal(err)
+	}
+	want := clix.Source{Kind: clix.SourceEnv, Name: "CLIX_TEST_SOURCE_GENERIC"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+	if !isSet {
+		t.Fatal("IsSet()=false, want true")
+	}
+}
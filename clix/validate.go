@@ -0,0 +1,122 @@
+package clix
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+// FlagError represents an error of the value of a flag.
+type FlagError struct {
+	// Flag is the flag whose value is invalid.
+	Flag cli.Flag
+
+	// Source is where the value came from.
+	Source Source
+
+	// Err is the reason why the value is invalid.
+	Err error
+}
+
+// Error returns the message with the name, the environment variables and
+// the source of the flag.
+//
+//	e.g.
+//	"--port [$APP_PORT] (env:APP_PORT): 70000 is out of range [1, 65535]"
+func (e *FlagError) Error() string {
+	return describeFlag(e.Flag) + " (" + e.Source.String() + "): " + e.Err.Error()
+}
+
+// Unwrap returns e.Err.
+func (e *FlagError) Unwrap() error {
+	return e.Err
+}
+
+// Check returns the Constraint that fn accepts the value of v.
+// The error returned by fn is reported as *FlagError.
+func Check[T any](h Handle[T], fn func(T) error) Constraint {
+	v := h.handle()
+	return func(fs FlagSet) error {
+		if err := fn(v.Value()); err != nil {
+			return &FlagError{Flag: v.Flag, Source: fs.Source(v.Flag), Err: err}
+		}
+		return nil
+	}
+}
+
+// CheckIfSet returns the Constraint that fn accepts the value of v if v is set.
+func CheckIfSet[T any](h Handle[T], fn func(T) error) Constraint {
+	v := h.handle()
+	check := Check[T](v, fn)
+	return func(fs FlagSet) error {
+		if !fs.IsSet(v.Flag) {
+			return nil
+		}
+		return check(fs)
+	}
+}
+
+// FileExists returns nil if path is a regular file or a symbolic link to it.
+func FileExists(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
+
+// FilesExist returns FileExists of all paths together as Errors.
+func FilesExist(paths []string) error {
+	var errs Errors
+	for _, path := range paths {
+		if err := FileExists(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.Err()
+}
+
+// IntRange returns the function that returns non-nil error if the value is
+// out of the range [min, max].
+func IntRange(min, max int) func(int) error {
+	return func(v int) error {
+		if v < min || max < v {
+			return fmt.Errorf("%d is out of range [%d, %d]", v, min, max)
+		}
+		return nil
+	}
+}
+
+// Validator is implemented by the groups of flags to validate their flags.
+type Validator interface {
+	// Validate returns all the problems of the flags in c.
+	Validate(c *cli.Context) error
+}
+
+// ValidateAll returns a function that calls Validate of all of validator,
+// returns all the problems together as Errors.
+// ValidateAll is intended to be used as cli.BeforeFunc.
+func ValidateAll(validator ...Validator) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		var errs Errors
+		for _, v := range validator {
+			errs = appendErrors(errs, v.Validate(c))
+		}
+		return errs.Err()
+	}
+}
+
+// appendErrors appends err to errs, flattening err if it is Errors.
+func appendErrors(errs Errors, err error) Errors {
+	if err == nil {
+		return errs
+	}
+	if list, ok := err.(Errors); ok {
+		return append(errs, list...)
+	}
+	return append(errs, err)
+}
@@ -0,0 +1,78 @@
+package clix_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+func TestValidateAll(t *testing.T) {
+	dir := t.TempDir()
+	cert := filepath.Join(dir, "cert.pem")
+	if err := os.WriteFile(cert, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("CLIX_TEST_VALIDATE_SERVER_PORT", "70000")
+	t.Cleanup(func() { os.Unsetenv("CLIX_TEST_VALIDATE_SERVER_PORT") })
+
+	prefix := clix.FlagPrefix("CLIX_TEST_VALIDATE_")
+
+	server := clix.NewGroup(prefix, "server")
+	port := server.Int(&cli.IntFlag{Name: "port", Value: 80})
+	certs := server.StringSlice(&cli.StringSliceFlag{Name: "tls-cert", Aliases: []string{"tlscrt"}})
+	keys := server.StringSlice(&cli.StringSliceFlag{Name: "tls-key", Aliases: []string{"tlskey"}})
+	server.Constrain(
+		clix.Check(port, clix.IntRange(1, 65535)),
+		clix.CheckIfSet(certs, clix.FilesExist),
+		func(clix.FlagSet) error {
+			if len(certs.Value()) != len(keys.Value()) {
+				return fmt.Errorf("the number of %q and %q must match", certs.Flag.Names()[0], keys.Flag.Names()[0])
+			}
+			return nil
+		},
+	)
+
+	client := clix.NewGroup(prefix, "client")
+	retry := client.Int(&cli.IntFlag{Name: "retry"})
+	client.Constrain(clix.Check(retry, clix.IntRange(0, 10)))
+
+	app := cli.NewApp()
+	app.Flags = clix.Flags(server.Flags(), client.Flags())
+	app.Before = clix.ValidateAll(server, client)
+	app.Action = func(*cli.Context) error { return nil }
+	err := app.Run([]string{"prog",
+		"--server-tls-cert", cert,
+		"--server-tls-cert", filepath.Join(dir, "none.pem"),
+		"--server-tls-key", "key.pem",
+		"--client-retry", "11",
+	})
+	if err == nil {
+		t.Fatal("no error")
+	}
+
+	want := strings.Join([]string{
+		"--server-port [$CLIX_TEST_VALIDATE_SERVER_PORT, $CLIX_TEST_VALIDATE_SERVER_P] (env:CLIX_TEST_VALIDATE_SERVER_PORT): 70000 is out of range [1, 65535]",
+		"--server-tls-cert [$CLIX_TEST_VALIDATE_SERVER_TLS_CERT, $CLIX_TEST_VALIDATE_SERVER_TLSCRT] (arg:--server-tls-cert): stat " + filepath.Join(dir, "none.pem") + ": no such file or directory",
+		`the number of "server-tls-cert" and "server-tls-key" must match`,
+		"--client-retry [$CLIX_TEST_VALIDATE_CLIENT_RETRY, $CLIX_TEST_VALIDATE_CLIENT_R] (arg:--client-retry): 11 is out of range [0, 10]",
+	}, "\n")
+	if diff := cmp.Diff(want, err.Error()); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+
+	var flagErr *clix.FlagError
+	if !errors.As(err, &flagErr) || flagErr.Flag != port.Flag {
+		t.Errorf("errors.As(err, &flagErr)=%v", flagErr)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Error("errors.Is(err, os.ErrNotExist)=false")
+	}
+}
@@ -0,0 +1,124 @@
+package clix
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Var is a flag with the accessors of its value.
+type Var[T any] struct {
+	// Flag is the flag holding the value.
+	Flag cli.Flag
+
+	// FlagSet is clix.FlagSet used to know whether Flag is set.
+	FlagSet FlagSet
+
+	value func() T
+}
+
+// Handle is implemented by *Var[T] and the handles returned by Group that
+// embed it, such as *IntVar.
+type Handle[T any] interface {
+	handle() *Var[T]
+}
+
+// handle returns v.
+func (v *Var[T]) handle() *Var[T] {
+	return v
+}
+
+// NewVar returns *Var[T] that returns the result of calling value as the
+// value of flag.
+func NewVar[T any](flag cli.Flag, fs FlagSet, value func() T) *Var[T] {
+	return &Var[T]{Flag: flag, FlagSet: fs, value: value}
+}
+
+// PtrVar returns *Var[T] that returns *p as the value of flag.
+// p is typically the Destination of flag.
+func PtrVar[T any](flag cli.Flag, fs FlagSet, p *T) *Var[T] {
+	return NewVar(flag, fs, func() T { return *p })
+}
+
+// NewStringVar returns *Var[string] of flag.
+// flag.Destination is set if it is nil.
+func NewStringVar(flag *cli.StringFlag, fs FlagSet) *Var[string] {
+	if flag.Destination == nil {
+		flag.Destination = new(string)
+	}
+	return PtrVar(flag, fs, flag.Destination)
+}
+
+// NewBoolVar returns *Var[bool] of flag.
+// flag.Destination is set if it is nil.
+func NewBoolVar(flag *cli.BoolFlag, fs FlagSet) *Var[bool] {
+	if flag.Destination == nil {
+		flag.Destination = new(bool)
+	}
+	return PtrVar(flag, fs, flag.Destination)
+}
+
+// NewIntVar returns *Var[int] of flag.
+// flag.Destination is set if it is nil.
+func NewIntVar(flag *cli.IntFlag, fs FlagSet) *Var[int] {
+	if flag.Destination == nil {
+		flag.Destination = new(int)
+	}
+	return PtrVar(flag, fs, flag.Destination)
+}
+
+// NewDurationVar returns *Var[time.Duration] of flag.
+// flag.Destination is set if it is nil.
+func NewDurationVar(flag *cli.DurationFlag, fs FlagSet) *Var[time.Duration] {
+	if flag.Destination == nil {
+		flag.Destination = new(time.Duration)
+	}
+	return PtrVar(flag, fs, flag.Destination)
+}
+
+// NewStringSliceVar returns *Var[[]string] of flag.
+// flag.Destination is set if it is nil.
+func NewStringSliceVar(flag *cli.StringSliceFlag, fs FlagSet) *Var[[]string] {
+	if flag.Destination == nil {
+		flag.Destination = new(cli.StringSlice)
+	}
+	dst := flag.Destination
+	return NewVar(flag, fs, dst.Value)
+}
+
+// NewGenericVar returns *Var[T] of flag whose Value is of type *T,
+// panic if it is not.
+//
+//	e.g.
+//	NewGenericVar[zapcore.Level](&cli.GenericFlag{Value: new(zapcore.Level)}, fs)
+func NewGenericVar[T any](flag *cli.GenericFlag, fs FlagSet) *Var[T] {
+	p, ok := interface{}(flag.Value).(*T)
+	if !ok {
+		panic(fmt.Sprintf("flag %q: Value is %T, not of type %T", flag.Name, flag.Value, p))
+	}
+	return PtrVar(flag, fs, p)
+}
+
+// Value returns the value of v.Flag.
+func (v *Var[T]) Value() T {
+	return v.value()
+}
+
+// Lookup returns the value of v.Flag.
+// If the flag is set by the environment variable or the command line argument
+// the value is returned and the boolean is true.
+// Otherwise the returned value is the default and the boolean will be false.
+func (v *Var[T]) Lookup() (T, bool) {
+	return v.Value(), v.IsSet()
+}
+
+// IsSet returns v.FlagSet.IsSet(v.Flag).
+func (v *Var[T]) IsSet() bool {
+	return v.FlagSet.IsSet(v.Flag)
+}
+
+// Source returns v.FlagSet.Source(v.Flag).
+func (v *Var[T]) Source() Source {
+	return v.FlagSet.Source(v.Flag)
+}
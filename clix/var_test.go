@@ -0,0 +1,68 @@
+package clix_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+type testLevel int
+
+func (l *testLevel) Set(value string) error {
+	*l = testLevel(len(value))
+	return nil
+}
+
+func (l *testLevel) String() string {
+	return string(rune('0' + int(*l)))
+}
+
+func TestVar(t *testing.T) {
+	fs := clix.NewFlagSet()
+
+	name := clix.NewStringVar(&cli.StringFlag{Name: "name", Value: "alice"}, fs)
+	port := clix.NewIntVar(&cli.IntFlag{Name: "port", EnvVars: []string{"CLIX_TEST_VAR_PORT"}}, fs)
+	level := clix.NewGenericVar[testLevel](&cli.GenericFlag{Name: "level", Value: new(testLevel)}, fs)
+
+	os.Setenv("CLIX_TEST_VAR_PORT", "8080")
+	t.Cleanup(func() { os.Unsetenv("CLIX_TEST_VAR_PORT") })
+
+	type value struct {
+		Name     string
+		NameSet  bool
+		Port     int
+		PortSet  bool
+		Level    testLevel
+		LevelSet bool
+	}
+	want := value{"alice", false, 8080, true, 3, true}
+
+	var got value
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{name.Flag, port.Flag, level.Flag}
+	app.Before = fs.Init
+	app.Action = func(c *cli.Context) error {
+		got.Name, got.NameSet = name.Lookup()
+		got.Port, got.PortSet = port.Lookup()
+		got.Level, got.LevelSet = level.Value(), level.IsSet()
+		return nil
+	}
+	if err := app.Run([]string{"prog", "--level", "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+}
+
+func TestNewGenericVar_panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic")
+		}
+	}()
+	clix.NewGenericVar[int](&cli.GenericFlag{Name: "level", Value: new(testLevel)}, clix.NewFlagSet())
+}
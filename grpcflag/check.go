@@ -0,0 +1,40 @@
+package grpcflag
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/urfave/cli/v2"
+)
+
+// checkFilesExist returns the constraints that the files of each of flags
+// exist if it is set.
+func checkFilesExist(fs clix.FlagSet, flags ...*cli.StringSliceFlag) []clix.Constraint {
+	list := make([]clix.Constraint, len(flags))
+	for i, flag := range flags {
+		list[i] = clix.CheckIfSet(clix.NewStringSliceVar(flag, fs), clix.FilesExist)
+	}
+	return list
+}
+
+// checkPort returns the constraint that the port of address is in the range
+// [0, 65535] if address is set and network returns a TCP or UDP network.
+// The names of the services such as "http" are left to the resolver.
+func checkPort(fs clix.FlagSet, network func() string, address *cli.StringFlag) clix.Constraint {
+	inRange := clix.IntRange(0, 65535)
+	return clix.CheckIfSet(clix.NewStringVar(address, fs), func(addr string) error {
+		if n := network(); !strings.HasPrefix(n, "tcp") && !strings.HasPrefix(n, "udp") {
+			return nil
+		}
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return err
+		}
+		if v, err := strconv.Atoi(port); err == nil {
+			return inRange(v)
+		}
+		return nil
+	})
+}
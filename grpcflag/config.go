@@ -3,6 +3,8 @@ package grpcflag
 import (
 	"crypto/tls"
 	"strings"
+
+	"github.com/takumakei/go-urfave-cli/clix"
 )
 
 type Config struct {
@@ -11,6 +13,7 @@ type Config struct {
 	Reflection    bool
 	TLSMinVersion uint16
 	TLSMaxVersion uint16
+	Naming        clix.Naming
 }
 
 func NewConfig(opt ...Option) Config {
@@ -28,6 +31,13 @@ func (c *Config) Apply(opts []Option) {
 	}
 }
 
+// FlagName returns the name of the flag by c.Naming with prefix.
+func (c *Config) FlagName(prefix clix.FlagPrefix, group, name, alias string) *clix.FlagName {
+	n := c.Naming
+	n.Prefix = prefix
+	return n.NewFlagNameDefault(group, name, alias)
+}
+
 func (c *Config) NetworkPredetermined() bool {
 	return len(c.Network) == 0 || (len(c.Network) == 1 && c.Network[0] != "*")
 }
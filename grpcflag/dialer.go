@@ -8,7 +8,6 @@ import (
 	"io/ioutil"
 	"net"
 
-	"github.com/takumakei/go-delint"
 	"github.com/takumakei/go-urfave-cli/clix"
 	"github.com/urfave/cli/v2"
 	"google.golang.org/grpc"
@@ -44,17 +43,17 @@ func NewDialerName(prefix clix.FlagPrefix, name string, opt ...Option) *Dialer {
 	cfg := NewConfig(opt...)
 
 	var (
-		nameFlagNetwork       = clix.NewFlagNameAlias(prefix, name, "network", "net")
-		nameFlagAddress       = clix.NewFlagNameAlias(prefix, name, "address", "addr")
-		nameFlagInsecure      = clix.NewFlagNameAlias(prefix, name, "with-insecure", "insecure")
-		nameFlagBlock         = clix.NewFlagNameAlias(prefix, name, "with-block", "block")
-		nameFlagTLSRootCAs    = clix.NewFlagNameAlias(prefix, name, "tls-root-ca", "tlsca")
-		nameFlagTLSServerName = clix.NewFlagNameAlias(prefix, name, "tls-server-name", "tlsserver")
-		nameFlagTLSSkipVerify = clix.NewFlagNameAlias(prefix, name, "tls-skip-verify", "tlsinsecure")
-		nameFlagTLSCerts      = clix.NewFlagNameAlias(prefix, name, "tls-cert", "tlscrt")
-		nameFlagTLSCertKeys   = clix.NewFlagNameAlias(prefix, name, "tls-cert-key", "tlskey")
-		nameFlagTLSMinVersion = clix.NewFlagNameAlias(prefix, name, "tls-min-version", "tlsmin")
-		nameFlagTLSMaxVersion = clix.NewFlagNameAlias(prefix, name, "tls-max-version", "tlsmax")
+		nameFlagNetwork       = cfg.FlagName(prefix, name, "network", "net")
+		nameFlagAddress       = cfg.FlagName(prefix, name, "address", "addr")
+		nameFlagInsecure      = cfg.FlagName(prefix, name, "with-insecure", "insecure")
+		nameFlagBlock         = cfg.FlagName(prefix, name, "with-block", "block")
+		nameFlagTLSRootCAs    = cfg.FlagName(prefix, name, "tls-root-ca", "tlsca")
+		nameFlagTLSServerName = cfg.FlagName(prefix, name, "tls-server-name", "tlsserver")
+		nameFlagTLSSkipVerify = cfg.FlagName(prefix, name, "tls-skip-verify", "tlsinsecure")
+		nameFlagTLSCerts      = cfg.FlagName(prefix, name, "tls-cert", "tlscrt")
+		nameFlagTLSCertKeys   = cfg.FlagName(prefix, name, "tls-cert-key", "tlskey")
+		nameFlagTLSMinVersion = cfg.FlagName(prefix, name, "tls-min-version", "tlsmin")
+		nameFlagTLSMaxVersion = cfg.FlagName(prefix, name, "tls-max-version", "tlsmax")
 	)
 
 	network := cfg.NetworkValue()
@@ -190,9 +189,24 @@ func (f *Dialer) Flags() []cli.Flag {
 	)
 }
 
+var _ clix.Validator = (*Dialer)(nil)
+
+func (f *Dialer) Validate(c *cli.Context) error {
+	return f.FlagSet.Validate(f.Constraints()...)(c)
+}
+
 func (f *Dialer) Before(c *cli.Context) error {
-	delint.Must(f.FlagSet.Init(c))
-	return nil
+	return f.Validate(c)
+}
+
+func (f *Dialer) Constraints() []clix.Constraint {
+	constraints := []clix.Constraint{
+		checkKeyPairs(f.FlagTLSCerts, f.FlagTLSCertKeys),
+		checkTLSVersions(f.FlagTLSMinVersion, f.FlagTLSMaxVersion),
+	}
+	// The address is a target of gRPC such as "dns:///localhost:50051",
+	// whose port is not checked.
+	return append(constraints, checkFilesExist(f.FlagSet, f.FlagTLSRootCAs, f.FlagTLSCerts, f.FlagTLSCertKeys)...)
 }
 
 func (f *Dialer) Network() string {
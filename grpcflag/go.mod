@@ -1,13 +1,30 @@
 module github.com/takumakei/go-urfave-cli/grpcflag
 
-go 1.16
+go 1.21
 
 require (
 	github.com/takumakei/go-cert4now v0.0.0-20210517105643-76d4e400f66b
-	github.com/takumakei/go-delint v0.0.0-20210515134037-9bbafd7915e7
 	github.com/takumakei/go-exit v0.0.0-20210429095029-8c3e71abac7f
-	github.com/takumakei/go-urfave-cli/clix v0.0.0-20210517060229-9df04e3952d0
+	github.com/takumakei/go-urfave-cli/clix v0.1.0
 	github.com/urfave/cli/v2 v2.3.0
 	google.golang.org/grpc v1.37.1
 	google.golang.org/grpc/examples v0.0.0-20210518222651-23a83dd097ec
 )
+
+require (
+	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
+	github.com/golang/protobuf v1.4.3 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	golang.org/x/net v0.0.0-20190311183353-d8887717615a // indirect
+	golang.org/x/sys v0.0.0-20190215142949-d0b11bdaac8a // indirect
+	golang.org/x/text v0.3.0 // indirect
+	google.golang.org/genproto v0.0.0-20200806141610-86f49bd18e98 // indirect
+	google.golang.org/protobuf v1.25.0 // indirect
+	gopkg.in/yaml.v2 v2.2.3 // indirect
+)
+
+// The clix APIs used here (Validator, Constraint and Errors) are released in
+// clix/v0.1.0, which must be tagged before this module. The replace directive
+// only applies to the builds in this repository.
+replace github.com/takumakei/go-urfave-cli/clix => ../clix
@@ -1,5 +1,7 @@
 package grpcflag
 
+import "github.com/takumakei/go-urfave-cli/clix"
+
 type Option func(*Config)
 
 func Network(network ...string) Option {
@@ -47,3 +49,11 @@ func Reflection(v bool) Option {
 		c.Reflection = v
 	}
 }
+
+// Naming returns the option to name the flags by n. The Prefix of n is
+// replaced by the prefix given to the constructor.
+func Naming(n clix.Naming) Option {
+	return func(c *Config) {
+		c.Naming = n
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"os"
 
 	"github.com/takumakei/go-cert4now"
-	"github.com/takumakei/go-delint"
 	"github.com/takumakei/go-urfave-cli/clix"
 	"github.com/urfave/cli/v2"
 	"google.golang.org/grpc"
@@ -50,16 +49,16 @@ func NewServerName(prefix clix.FlagPrefix, name string, opt ...Option) *Server {
 	cfg := NewConfig(opt...)
 
 	var (
-		nameFlagNetwork         = clix.NewFlagNameAlias(prefix, name, "network", "net")
-		nameFlagAddress         = clix.NewFlagNameAlias(prefix, name, "address", "addr")
-		nameFlagReflection      = clix.NewFlagNameAlias(prefix, name, "grpc-reflection", "reflection")
-		nameFlagTLSCerts        = clix.NewFlagNameAlias(prefix, name, "tls-cert", "tlscrt")
-		nameFlagTLSCertKeys     = clix.NewFlagNameAlias(prefix, name, "tls-cert-key", "tlskey")
-		nameFlagTLSGenCert      = clix.NewFlagNameAlias(prefix, name, "tls-gen-cert", "tlsgen")
-		nameFlagTLSVerifyClient = clix.NewFlagNameAlias(prefix, name, "tls-verify-client", "mtls")
-		nameFlagTLSClientCAs    = clix.NewFlagNameAlias(prefix, name, "tls-client-ca", "tlsca")
-		nameFlagTLSMinVersion   = clix.NewFlagNameAlias(prefix, name, "tls-min-version", "tlsmin")
-		nameFlagTLSMaxVersion   = clix.NewFlagNameAlias(prefix, name, "tls-max-version", "tlsmax")
+		nameFlagNetwork         = cfg.FlagName(prefix, name, "network", "net")
+		nameFlagAddress         = cfg.FlagName(prefix, name, "address", "addr")
+		nameFlagReflection      = cfg.FlagName(prefix, name, "grpc-reflection", "reflection")
+		nameFlagTLSCerts        = cfg.FlagName(prefix, name, "tls-cert", "tlscrt")
+		nameFlagTLSCertKeys     = cfg.FlagName(prefix, name, "tls-cert-key", "tlskey")
+		nameFlagTLSGenCert      = cfg.FlagName(prefix, name, "tls-gen-cert", "tlsgen")
+		nameFlagTLSVerifyClient = cfg.FlagName(prefix, name, "tls-verify-client", "mtls")
+		nameFlagTLSClientCAs    = cfg.FlagName(prefix, name, "tls-client-ca", "tlsca")
+		nameFlagTLSMinVersion   = cfg.FlagName(prefix, name, "tls-min-version", "tlsmin")
+		nameFlagTLSMaxVersion   = cfg.FlagName(prefix, name, "tls-max-version", "tlsmax")
 	)
 
 	network := cfg.NetworkValue()
@@ -186,23 +185,25 @@ func (f *Server) Flags() []cli.Flag {
 	)
 }
 
+var _ clix.Validator = (*Server)(nil)
+
+func (f *Server) Validate(c *cli.Context) error {
+	return f.FlagSet.Validate(f.Constraints()...)(c)
+}
+
 func (f *Server) Before(c *cli.Context) error {
-	delint.Must(f.FlagSet.Init(c))
-	if f.FlagSet.IsSet(f.FlagTLSCerts) && f.TLSGenCert() {
-		return fmt.Errorf(
-			"%q and %q must not set at the same time",
-			f.FlagTLSCerts.Name,
-			f.FlagTLSGenCert.Name,
-		)
-	}
-	if f.FlagSet.IsSet(f.FlagTLSCertKeys) && f.TLSGenCert() {
-		return fmt.Errorf(
-			"%q and %q must not set at the same time",
-			f.FlagTLSCertKeys.Name,
-			f.FlagTLSGenCert.Name,
-		)
+	return f.Validate(c)
+}
+
+func (f *Server) Constraints() []clix.Constraint {
+	genCert := func(clix.FlagSet) bool { return f.TLSGenCert() }
+	constraints := []clix.Constraint{
+		clix.ForbiddenIf("--"+f.FlagTLSGenCert.Name+" is true", genCert, f.FlagTLSCerts, f.FlagTLSCertKeys),
+		checkKeyPairs(f.FlagTLSCerts, f.FlagTLSCertKeys),
+		checkTLSVersions(f.FlagTLSMinVersion, f.FlagTLSMaxVersion),
+		checkPort(f.FlagSet, f.Network, f.FlagAddress),
 	}
-	return nil
+	return append(constraints, checkFilesExist(f.FlagSet, f.FlagTLSCerts, f.FlagTLSCertKeys, f.FlagTLSClientCAs)...)
 }
 
 func (f *Server) Network() string {
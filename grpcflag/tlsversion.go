@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 
+	"github.com/takumakei/go-urfave-cli/clix"
 	"github.com/urfave/cli/v2"
 )
 
@@ -56,3 +57,33 @@ func (tv *TLSVersion) String() string {
 func (tv *TLSVersion) Value() uint16 {
 	return tv.ver
 }
+
+// checkTLSVersions returns the constraint that the TLS version of min is not
+// greater than the one of max. The problem is reported as *clix.FlagError.
+func checkTLSVersions(min, max *cli.GenericFlag) clix.Constraint {
+	return func(fs clix.FlagSet) error {
+		if min.Value.(*TLSVersion).Value() > max.Value.(*TLSVersion).Value() {
+			err := fmt.Errorf("%s is greater than %s of --%s", min.Value, max.Value, max.Name)
+			return &clix.FlagError{Flag: min, Source: fs.Source(min), Err: err}
+		}
+		return nil
+	}
+}
+
+// checkKeyPairs returns the constraint that the number of certs and keys
+// match. The problem is reported as *clix.FlagError of keys, or certs if no
+// key is given.
+func checkKeyPairs(certs, keys *cli.StringSliceFlag) clix.Constraint {
+	return func(fs clix.FlagSet) error {
+		flag, other := keys, certs
+		n, m := len(keys.Destination.Value()), len(certs.Destination.Value())
+		if n == m {
+			return nil
+		}
+		if n == 0 {
+			flag, other, n, m = certs, keys, m, n
+		}
+		err := fmt.Errorf("%d given for %d of --%s", n, m, other.Name)
+		return &clix.FlagError{Flag: flag, Source: fs.Source(flag), Err: err}
+	}
+}
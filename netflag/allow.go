@@ -0,0 +1,98 @@
+package netflag
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotAllowed represents an error where the client certificate is not in
+// the allow-list.
+var ErrNotAllowed = errors.New("certificate is not allowed")
+
+// ClientAllowList is the list of the names of the client certificates that
+// are allowed to connect.
+//
+// A name is matched exactly against the following of the leaf certificate,
+// or only one of them if the name has the corresponding prefix.
+//
+//     cn:     the common name of the subject
+//     dn:     the subject in the form of pkix.Name.String()
+//     dns:    the DNS names in the SANs
+//     email:  the email addresses in the SANs
+//     uri:    the URIs in the SANs, including SPIFFE IDs
+//
+//     e.g.
+//     "alice", "cn:alice", "dns:api.example.com", "spiffe://example.org/web"
+type ClientAllowList []string
+
+// VerifyPeerCertificate returns ErrNotAllowed if the leaf certificates of
+// verifiedChains match none of l, including when verifiedChains is empty,
+// that is when the client sends no certificate.
+// VerifyPeerCertificate is intended to be used as
+// tls.Config.VerifyPeerCertificate along with tls.VerifyClientCertIfGiven
+// or tls.RequireAndVerifyClientCert.
+func (l ClientAllowList) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if len(verifiedChains) == 0 {
+		return fmt.Errorf("%w: no certificate", ErrNotAllowed)
+	}
+	for _, chain := range verifiedChains {
+		if l.Allows(chain[0]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrNotAllowed, verifiedChains[0][0].Subject)
+}
+
+// Allows returns true if cert matches any of l.
+func (l ClientAllowList) Allows(cert *x509.Certificate) bool {
+	for _, name := range l {
+		if allowsName(name, cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsName returns true if cert matches name.
+func allowsName(name string, cert *x509.Certificate) bool {
+	kind := ""
+	if i := strings.Index(name, ":"); i > 0 {
+		switch name[:i] {
+		case "cn", "dn", "dns", "email", "uri":
+			kind, name = name[:i], name[i+1:]
+		}
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if (kind == "" || kind == "cn") && cert.Subject.CommonName == name {
+		return true
+	}
+	if (kind == "" || kind == "dn") && cert.Subject.String() == name {
+		return true
+	}
+	if kind == "" || kind == "dns" {
+		for _, v := range cert.DNSNames {
+			if v == name {
+				return true
+			}
+		}
+	}
+	if kind == "" || kind == "email" {
+		for _, v := range cert.EmailAddresses {
+			if v == name {
+				return true
+			}
+		}
+	}
+	if kind == "" || kind == "uri" {
+		for _, v := range cert.URIs {
+			if v.String() == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -7,7 +7,6 @@ import (
 	"net"
 	"os"
 
-	"github.com/takumakei/go-delint"
 	"github.com/takumakei/go-urfave-cli/clix"
 	"github.com/urfave/cli/v2"
 )
@@ -50,6 +49,15 @@ type Client struct {
 	// FlagTLSMaxVer is the maximum TLS version that is acceptable.
 	FlagTLSMaxVer *cli.GenericFlag
 
+	// FlagTLSCipherSuites is the cipher suites of TLS 1.0-1.2 that are acceptable.
+	FlagTLSCipherSuites *cli.GenericFlag
+
+	// FlagTLSCurves is the elliptic curves in preference order.
+	FlagTLSCurves *cli.GenericFlag
+
+	// FlagTLSNextProtos is the ALPN protocols in preference order.
+	FlagTLSNextProtos *cli.GenericFlag
+
 	// FlagSet is clix.FlagSet.
 	FlagSet clix.FlagSet
 }
@@ -64,15 +72,18 @@ func NewClientName(prefix clix.FlagPrefix, name string, opts ...Option) *Client
 	cfg := newConfig(opts...)
 
 	var (
-		nameNetwork       = clix.NewFlagNameAlias(prefix, name, "network", "net")
-		nameAddress       = clix.NewFlagNameAlias(prefix, name, "address", "addr")
-		nameTLSCert       = clix.NewFlagNameAlias(prefix, name, "tls-cert", "tlscrt")
-		nameTLSKey        = clix.NewFlagNameAlias(prefix, name, "tls-cert-key", "tlskey")
-		nameTLSCAs        = clix.NewFlagNameAlias(prefix, name, "tls-ca", "tlsca")
-		nameTLSServerName = clix.NewFlagNameAlias(prefix, name, "tls-server-name", "tlssrv")
-		nameTLSSkipVerify = clix.NewFlagNameAlias(prefix, name, "tls-skip-verify", "tlsinsecure")
-		nameTLSMinVer     = clix.NewFlagNameAlias(prefix, name, "tls-min-version", "tlsmin")
-		nameTLSMaxVer     = clix.NewFlagNameAlias(prefix, name, "tls-max-version", "tlsmax")
+		nameNetwork       = cfg.flagName(prefix, name, "network", "net")
+		nameAddress       = cfg.flagName(prefix, name, "address", "addr")
+		nameTLSCert       = cfg.flagName(prefix, name, "tls-cert", "tlscrt")
+		nameTLSKey        = cfg.flagName(prefix, name, "tls-cert-key", "tlskey")
+		nameTLSCAs        = cfg.flagName(prefix, name, "tls-ca", "tlsca")
+		nameTLSServerName = cfg.flagName(prefix, name, "tls-server-name", "tlssrv")
+		nameTLSSkipVerify = cfg.flagName(prefix, name, "tls-skip-verify", "tlsinsecure")
+		nameTLSMinVer     = cfg.flagName(prefix, name, "tls-min-version", "tlsmin")
+		nameTLSMaxVer     = cfg.flagName(prefix, name, "tls-max-version", "tlsmax")
+		nameTLSCiphers    = cfg.flagName(prefix, name, "tls-cipher-suites", "tlsciphers")
+		nameTLSCurves     = cfg.flagName(prefix, name, "tls-curves", "tlscurves")
+		nameTLSALPN       = cfg.flagName(prefix, name, "tls-alpn", "tlsalpn")
 	)
 
 	network := cfg.networkValue()
@@ -173,15 +184,62 @@ func NewClientName(prefix clix.FlagPrefix, name string, opts ...Option) *Client
 			Value:    NewTLSVersion(cfg.tlsMaxVersion),
 		},
 
+		FlagTLSCipherSuites: &cli.GenericFlag{
+			Name:     nameTLSCiphers.Name,
+			Aliases:  nameTLSCiphers.Aliases,
+			Usage:    "comma separated IANA `names` of TLS 1.0-1.2 cipher suites",
+			EnvVars:  nameTLSCiphers.EnvVars,
+			FilePath: nameTLSCiphers.FilePath,
+			Value:    NewTLSCipherSuites(cfg.tlsCipherSuites...),
+		},
+
+		FlagTLSCurves: &cli.GenericFlag{
+			Name:     nameTLSCurves.Name,
+			Aliases:  nameTLSCurves.Aliases,
+			Usage:    "comma separated elliptic `curves` in preference order [X25519|P-256|P-384|P-521|X25519MLKEM768]",
+			EnvVars:  nameTLSCurves.EnvVars,
+			FilePath: nameTLSCurves.FilePath,
+			Value:    NewTLSCurves(cfg.tlsCurves...),
+		},
+
+		FlagTLSNextProtos: &cli.GenericFlag{
+			Name:     nameTLSALPN.Name,
+			Aliases:  nameTLSALPN.Aliases,
+			Usage:    "comma separated ALPN `protocols` in preference order",
+			EnvVars:  nameTLSALPN.EnvVars,
+			FilePath: nameTLSALPN.FilePath,
+			Value:    NewTLSNextProtos(cfg.tlsNextProtos...),
+		},
+
 		FlagSet: clix.NewFlagSet(),
 	}
 }
 
-// Before calls f.FlagSet.Init(c).
+var _ clix.Validator = (*Client)(nil)
+
+// Validate calls f.FlagSet.Init(c), then checks all of f.Constraints(),
+// returns all the violations together as clix.Errors.
+func (f *Client) Validate(c *cli.Context) error {
+	return f.FlagSet.Validate(f.Constraints()...)(c)
+}
+
+// Before calls f.Validate(c).
 // Before is intended to be used as cli.BeforeFunc.
 func (f *Client) Before(c *cli.Context) error {
-	delint.Must(f.FlagSet.Init(c))
-	return nil
+	return f.Validate(c)
+}
+
+// Constraints returns the constraints checked by Validate.
+func (f *Client) Constraints() []clix.Constraint {
+	constraints := []clix.Constraint{
+		checkKeyPairs(f.FlagTLSCerts, f.FlagTLSKeys),
+		checkPort(f.FlagSet, f.Network, f.FlagAddress),
+	}
+	constraints = append(constraints, checkFilesExist(f.FlagSet, f.FlagTLSCerts, f.FlagTLSKeys, f.FlagTLSCAs)...)
+	if !f.DisableTLS {
+		constraints = append(constraints, checkTLSFlags(f.FlagTLSMinVer, f.FlagTLSMaxVer, f.FlagTLSCipherSuites, f.FlagTLSCurves))
+	}
+	return constraints
 }
 
 // Flags returns []cli.Flag.
@@ -200,6 +258,9 @@ func (f *Client) Before(c *cli.Context) error {
 //     f.FlagTLSSkipVerify
 //     f.FlagTLSMinVer
 //     f.FlagTLSMaxVer
+//     f.FlagTLSCipherSuites
+//     f.FlagTLSCurves
+//     f.FlagTLSNextProtos
 func (f *Client) Flags() []cli.Flag {
 	return clix.Flags(
 		clix.FlagIf(!f.PredeterminedFlagNetwork, f.FlagNetwork),
@@ -212,6 +273,9 @@ func (f *Client) Flags() []cli.Flag {
 			f.FlagTLSSkipVerify,
 			f.FlagTLSMinVer,
 			f.FlagTLSMaxVer,
+			f.FlagTLSCipherSuites,
+			f.FlagTLSCurves,
+			f.FlagTLSNextProtos,
 		)...),
 	)
 }
@@ -260,6 +324,21 @@ func (f *Client) TLSMaxVersion() uint16 {
 	return f.FlagTLSMaxVer.Value.(*TLSVersion).Value()
 }
 
+// TLSCipherSuites returns the value of FlagTLSCipherSuites.
+func (f *Client) TLSCipherSuites() []uint16 {
+	return f.FlagTLSCipherSuites.Value.(*TLSCipherSuites).Value()
+}
+
+// TLSCurvePreferences returns the value of FlagTLSCurves.
+func (f *Client) TLSCurvePreferences() []tls.CurveID {
+	return f.FlagTLSCurves.Value.(*TLSCurves).Value()
+}
+
+// TLSNextProtos returns the value of FlagTLSNextProtos.
+func (f *Client) TLSNextProtos() []string {
+	return f.FlagTLSNextProtos.Value.(*TLSNextProtos).Value()
+}
+
 // UseTLS returns true if TLS related flags are presented.
 func (f *Client) UseTLS() bool {
 	list := []cli.Flag{
@@ -270,6 +349,9 @@ func (f *Client) UseTLS() bool {
 		f.FlagTLSSkipVerify,
 		f.FlagTLSMinVer,
 		f.FlagTLSMaxVer,
+		f.FlagTLSCipherSuites,
+		f.FlagTLSCurves,
+		f.FlagTLSNextProtos,
 	}
 	for _, flag := range list {
 		if f.FlagSet.IsSet(flag) {
@@ -318,6 +400,9 @@ func (f *Client) TLSConfig() (*tls.Config, error) {
 		InsecureSkipVerify: f.TLSSkipVerify(),
 		MinVersion:         f.TLSMinVersion(),
 		MaxVersion:         f.TLSMaxVersion(),
+		CipherSuites:       f.TLSCipherSuites(),
+		CurvePreferences:   f.TLSCurvePreferences(),
+		NextProtos:         f.TLSNextProtos(),
 		ServerName:         f.TLSServerName(),
 	}
 
@@ -38,14 +38,17 @@ func ExampleClient() {
 	//    help, h  Shows a list of commands or help for one command
 	//
 	// GLOBAL OPTIONS:
-	//    --echo-network value, --echo-net value             network to connect (default: "udp") [$EXAMPLE_ECHO_NETWORK, $EXAMPLE_ECHO_NET]
-	//    --echo-address value, --echo-addr value            address to connect (default: "127.0.0.1:9000") [$EXAMPLE_ECHO_ADDRESS, $EXAMPLE_ECHO_ADDR]
-	//    --echo-tls-cert file, --echo-tlscrt file           certificate file [$EXAMPLE_ECHO_TLS_CERT, $EXAMPLE_ECHO_TLSCRT]
-	//    --echo-tls-cert-key file, --echo-tlskey file       private key file of certificate [$EXAMPLE_ECHO_TLS_CERT_KEY, $EXAMPLE_ECHO_TLSKEY]
-	//    --echo-tls-ca file, --echo-tlsca file              root CA file of server [$EXAMPLE_ECHO_TLS_CA, $EXAMPLE_ECHO_TLSCA]
-	//    --echo-tls-server-name value, --echo-tlssrv value  server name for verification [$EXAMPLE_ECHO_TLS_SERVER_NAME, $EXAMPLE_ECHO_TLSSRV]
-	//    --echo-tls-skip-verify, --echo-tlsinsecure         TLS insecure skip verify (default: false) [$EXAMPLE_ECHO_TLS_SKIP_VERIFY, $EXAMPLE_ECHO_TLSINSECURE]
-	//    --echo-tls-min-version value, --echo-tlsmin value  TLS minimum version (default: 1.2) [$EXAMPLE_ECHO_TLS_MIN_VERSION, $EXAMPLE_ECHO_TLSMIN]
-	//    --echo-tls-max-version value, --echo-tlsmax value  TLS maximum version (default: 1.3) [$EXAMPLE_ECHO_TLS_MAX_VERSION, $EXAMPLE_ECHO_TLSMAX]
-	//    --help, -h                                         show help (default: false)
+	//    --echo-network value, --echo-net value                   network to connect (default: "udp") [$EXAMPLE_ECHO_NETWORK, $EXAMPLE_ECHO_NET]
+	//    --echo-address value, --echo-addr value                  address to connect (default: "127.0.0.1:9000") [$EXAMPLE_ECHO_ADDRESS, $EXAMPLE_ECHO_ADDR]
+	//    --echo-tls-cert file, --echo-tlscrt file                 certificate file [$EXAMPLE_ECHO_TLS_CERT, $EXAMPLE_ECHO_TLSCRT]
+	//    --echo-tls-cert-key file, --echo-tlskey file             private key file of certificate [$EXAMPLE_ECHO_TLS_CERT_KEY, $EXAMPLE_ECHO_TLSKEY]
+	//    --echo-tls-ca file, --echo-tlsca file                    root CA file of server [$EXAMPLE_ECHO_TLS_CA, $EXAMPLE_ECHO_TLSCA]
+	//    --echo-tls-server-name value, --echo-tlssrv value        server name for verification [$EXAMPLE_ECHO_TLS_SERVER_NAME, $EXAMPLE_ECHO_TLSSRV]
+	//    --echo-tls-skip-verify, --echo-tlsinsecure               TLS insecure skip verify (default: false) [$EXAMPLE_ECHO_TLS_SKIP_VERIFY, $EXAMPLE_ECHO_TLSINSECURE]
+	//    --echo-tls-min-version value, --echo-tlsmin value        TLS minimum version (default: 1.2) [$EXAMPLE_ECHO_TLS_MIN_VERSION, $EXAMPLE_ECHO_TLSMIN]
+	//    --echo-tls-max-version value, --echo-tlsmax value        TLS maximum version (default: 1.3) [$EXAMPLE_ECHO_TLS_MAX_VERSION, $EXAMPLE_ECHO_TLSMAX]
+	//    --echo-tls-cipher-suites names, --echo-tlsciphers names  comma separated IANA names of TLS 1.0-1.2 cipher suites [$EXAMPLE_ECHO_TLS_CIPHER_SUITES, $EXAMPLE_ECHO_TLSCIPHERS]
+	//    --echo-tls-curves curves, --echo-tlscurves curves        comma separated elliptic curves in preference order [X25519|P-256|P-384|P-521|X25519MLKEM768] [$EXAMPLE_ECHO_TLS_CURVES, $EXAMPLE_ECHO_TLSCURVES]
+	//    --echo-tls-alpn protocols, --echo-tlsalpn protocols      comma separated ALPN protocols in preference order [$EXAMPLE_ECHO_TLS_ALPN, $EXAMPLE_ECHO_TLSALPN]
+	//    --help, -h                                               show help (default: false)
 }
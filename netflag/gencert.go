@@ -0,0 +1,322 @@
+package netflag
+
+import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/takumakei/go-cert4now"
+)
+
+// The names of the files in CertGenerator.Dir.
+const (
+	GenCertCAFile      = "ca.pem"
+	GenCertCAKeyFile   = "ca-key.pem"
+	GenCertCertFile    = "cert.pem"
+	GenCertCertKeyFile = "cert-key.pem"
+)
+
+// CertGenerator generates a self-signed certificate.
+//
+// If Dir is not empty, a self-signed CA and a certificate issued by it are
+// kept in Dir, and reused on later runs. The certificate is regenerated when
+// it expires within RenewBefore, or SANs are changed. The clients can trust
+// the certificate with the CA file, Dir/ca.pem.
+type CertGenerator struct {
+	// Name is the common name of the certificate.
+	Name string
+
+	// SANs is the DNS names and the IP addresses of the certificate.
+	SANs []string
+
+	// Dir is the directory to keep the CA and the certificate,
+	// nothing is kept if empty.
+	Dir string
+
+	// Validity is the period of validity of the certificate issued by the CA,
+	// 1 year if zero.
+	Validity time.Duration
+
+	// RenewBefore is the period before expiry to regenerate the certificate,
+	// 30 days if zero.
+	RenewBefore time.Duration
+}
+
+// CAFile returns the path of the CA file, empty string if g.Dir is empty.
+func (g *CertGenerator) CAFile() string {
+	if len(g.Dir) == 0 {
+		return ""
+	}
+	return filepath.Join(g.Dir, GenCertCAFile)
+}
+
+// Certificate returns the certificate.
+//
+// If g.Dir is empty, it generates a self-signed certificate valid for 100
+// years. Otherwise it loads the CA and the certificate from g.Dir, and
+// generates and writes them if they are missing, invalid or expiring.
+// The certificate does not outlive the CA.
+func (g *CertGenerator) Certificate() (tls.Certificate, error) {
+	cert, _, err := g.certificate()
+	return cert, err
+}
+
+// certificate returns the certificate and the CA that issued it.
+// The CA is nil if g.Dir is empty.
+func (g *CertGenerator) certificate() (tls.Certificate, *x509.Certificate, error) {
+	dnsNames, ips := splitSANs(g.SANs)
+	if len(g.Dir) == 0 {
+		cert, err := cert4now.Generate(
+			cert4now.CommonName(g.Name),
+			cert4now.ECDSA(elliptic.P384()),
+			cert4now.AddDate(100, 0, 0),
+			cert4now.DNSNames(dnsNames...),
+			cert4now.IPAddresses(ips...),
+		)
+		return cert, nil, err
+	}
+
+	if err := os.MkdirAll(g.Dir, 0700); err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	ca, caRenewed, err := g.loadOrGenerate(GenCertCAFile, GenCertCAKeyFile, nil, g.generateCA)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	// The certificate issued by the previous CA is not reusable.
+	var parent *x509.Certificate
+	if !caRenewed {
+		parent = ca.Leaf
+	}
+	cert, _, err := g.loadOrGenerate(GenCertCertFile, GenCertCertKeyFile, parent, func() (tls.Certificate, error) {
+		notAfter := time.Now().Add(g.validity())
+		if ca.Leaf.NotAfter.Before(notAfter) {
+			notAfter = ca.Leaf.NotAfter
+		}
+		return cert4now.Generate(
+			cert4now.Authority(ca),
+			cert4now.CommonName(g.Name),
+			cert4now.ECDSA(elliptic.P384()),
+			cert4now.NotAfter(notAfter),
+			cert4now.DNSNames(dnsNames...),
+			cert4now.IPAddresses(ips...),
+		)
+	})
+	return cert, ca.Leaf, err
+}
+
+// watchCertificate returns the function to be used as
+// tls.Config.GetCertificate, which returns the result of g.Certificate()
+// called every interval until ctx is done. onReload is called when the
+// certificate is renewed or g.Certificate() fails, may be nil. The files of
+// the CA are also in the event when the CA is renewed, so that the clients
+// can be told to trust the new one.
+func (g *CertGenerator) watchCertificate(ctx context.Context, interval time.Duration, onReload func(ReloadEvent)) (func(*tls.ClientHelloInfo) (*tls.Certificate, error), error) {
+	cert, ca, err := g.certificate()
+	if err != nil {
+		return nil, err
+	}
+	var current atomic.Value
+	current.Store(&cert)
+	certFiles := []string{filepath.Join(g.Dir, GenCertCertFile), filepath.Join(g.Dir, GenCertCertKeyFile)}
+	caFiles := []string{filepath.Join(g.Dir, GenCertCAFile), filepath.Join(g.Dir, GenCertCAKeyFile)}
+	go watch(ctx, interval, func() error {
+		files := certFiles
+		cert, newCA, err := g.certificate()
+		if err == nil {
+			if !bytes.Equal(newCA.Raw, ca.Raw) {
+				ca = newCA
+				files = append(append([]string(nil), caFiles...), certFiles...)
+			} else if bytes.Equal(cert.Certificate[0], current.Load().(*tls.Certificate).Certificate[0]) {
+				return nil
+			}
+			current.Store(&cert)
+		}
+		if onReload != nil {
+			onReload(ReloadEvent{Files: files, Err: err})
+		}
+		return err
+	})
+	return func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return current.Load().(*tls.Certificate), nil
+	}, nil
+}
+
+// generateCA returns a new self-signed CA.
+func (g *CertGenerator) generateCA() (tls.Certificate, error) {
+	name := "netflag CA"
+	if len(g.Name) > 0 {
+		name = g.Name + " CA"
+	}
+	return cert4now.Generate(
+		cert4now.CommonName(name),
+		cert4now.ECDSA(elliptic.P384()),
+		cert4now.AddDate(10, 0, 0),
+		cert4now.IsCA(true),
+		cert4now.KeyUsage(x509.KeyUsageCertSign|x509.KeyUsageCRLSign|x509.KeyUsageDigitalSignature),
+	)
+}
+
+// loadOrGenerate returns the pair loaded from certFile and keyFile in g.Dir
+// if it is reusable, otherwise the pair generated by generate after writing it
+// into the files. renewed is true if the pair is generated.
+//
+// The loaded pair is reusable if it is issued by parent (or it is a CA if
+// parent is nil), does not expire within g.RenewBefore, and has g.SANs.
+func (g *CertGenerator) loadOrGenerate(certFile, keyFile string, parent *x509.Certificate, generate func() (tls.Certificate, error)) (cert tls.Certificate, renewed bool, err error) {
+	certFile = filepath.Join(g.Dir, certFile)
+	keyFile = filepath.Join(g.Dir, keyFile)
+	if cert, err := loadKeyPair(certFile, keyFile); err == nil && g.reusable(cert.Leaf, parent) {
+		return cert, false, nil
+	}
+	if cert, err = generate(); err != nil {
+		return
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return
+	}
+	if err = writeKeyPair(certFile, keyFile, cert); err != nil {
+		return
+	}
+	return cert, true, nil
+}
+
+// reusable returns true if cert is issued by parent (or cert is a CA if parent
+// is nil), does not expire within g.RenewBefore, and has g.SANs.
+func (g *CertGenerator) reusable(cert, parent *x509.Certificate) bool {
+	if time.Until(cert.NotAfter) <= g.renewBefore() {
+		return false
+	}
+	if parent == nil {
+		return cert.IsCA
+	}
+	if err := cert.CheckSignatureFrom(parent); err != nil {
+		return false
+	}
+	dnsNames, ips := splitSANs(g.SANs)
+	return cert.Subject.CommonName == g.Name &&
+		equalStrings(dnsNames, cert.DNSNames) &&
+		equalStrings(ipStrings(ips), ipStrings(cert.IPAddresses))
+}
+
+// validity returns g.Validity or its default.
+func (g *CertGenerator) validity() time.Duration {
+	if g.Validity > 0 {
+		return g.Validity
+	}
+	return 365 * 24 * time.Hour
+}
+
+// renewBefore returns g.RenewBefore or its default.
+func (g *CertGenerator) renewBefore() time.Duration {
+	if g.RenewBefore > 0 {
+		return g.RenewBefore
+	}
+	return 30 * 24 * time.Hour
+}
+
+// loadKeyPair returns the pair loaded from certFile and keyFile with Leaf.
+func loadKeyPair(certFile, keyFile string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return cert, err
+	}
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	return cert, err
+}
+
+// writeKeyPair writes cert into certFile and its private key into keyFile.
+// Each file is replaced by renaming a temporary file.
+func writeKeyPair(certFile, keyFile string, cert tls.Certificate) error {
+	p, err := cert4now.EncodeCertificateToPEM(cert)
+	if err != nil {
+		return err
+	}
+	k, err := cert4now.EncodePrivateKeyToPEM(cert)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(keyFile, k, 0600); err != nil {
+		return err
+	}
+	return writeFileAtomic(certFile, p, 0644)
+}
+
+// writeFileAtomic writes p into a temporary file in the directory of file,
+// then renames it to file. The temporary file is removed on failure.
+func writeFileAtomic(file string, p []byte, perm os.FileMode) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %q, %w", file, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(p); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write %q, %w", file, err)
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write %q, %w", file, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %q, %w", file, err)
+	}
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to write %q, %w", file, err)
+	}
+	return nil
+}
+
+// splitSANs returns the DNS names and the IP addresses in sans.
+func splitSANs(sans []string) (dnsNames []string, ips []net.IP) {
+	for _, v := range sans {
+		if len(v) == 0 {
+			continue
+		}
+		if ip := net.ParseIP(v); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dnsNames = append(dnsNames, v)
+		}
+	}
+	return
+}
+
+// ipStrings returns the string representations of ips.
+func ipStrings(ips []net.IP) []string {
+	list := make([]string, len(ips))
+	for i, ip := range ips {
+		list[i] = ip.String()
+	}
+	return list
+}
+
+// equalStrings returns true if a and b have the same elements.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,241 @@
+package netflag_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/takumakei/go-urfave-cli/netflag"
+	"github.com/urfave/cli/v2"
+)
+
+func verifyGenCert(t *testing.T, cert tls.Certificate, caFile, name string) {
+	t.Helper()
+	p, err := os.ReadFile(caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(p) {
+		t.Fatal("no CA")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: name, Roots: roots}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertGenerator(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gen")
+	g := &netflag.CertGenerator{
+		Name: "example",
+		SANs: []string{"localhost", "127.0.0.1"},
+		Dir:  dir,
+	}
+	if diff := cmp.Diff(filepath.Join(dir, "ca.pem"), g.CAFile()); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+
+	first, err := g.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyGenCert(t, first, g.CAFile(), "localhost")
+	verifyGenCert(t, first, g.CAFile(), "127.0.0.1")
+	ca, err := os.ReadFile(g.CAFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "ca-key.pem")); err != nil || fi.Mode().Perm() != 0600 {
+		t.Fatalf("unexpected ca-key.pem %v %v", fi, err)
+	}
+
+	// reused
+	second, err := g.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Certificate[0], second.Certificate[0]) {
+		t.Fatal("certificate is not reused")
+	}
+
+	// SANs are changed
+	g.SANs = []string{"example.com"}
+	third, err := g.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(second.Certificate[0], third.Certificate[0]) {
+		t.Fatal("certificate is not regenerated")
+	}
+	verifyGenCert(t, third, g.CAFile(), "example.com")
+
+	// expiring
+	g.RenewBefore = 2 * time.Hour
+	g.Validity = time.Hour
+	g.SANs = []string{"example.com", "www.example.com"}
+	fourth, err := g.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fifth, err := g.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(fourth.Certificate[0], fifth.Certificate[0]) {
+		t.Fatal("certificate is not renewed")
+	}
+	verifyGenCert(t, fifth, g.CAFile(), "www.example.com")
+
+	// the CA is kept
+	p, err := os.ReadFile(g.CAFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ca, p) {
+		t.Fatal("CA is regenerated")
+	}
+
+	// the certificate does not outlive the CA
+	g.Validity = 20 * 365 * 24 * time.Hour
+	g.SANs = []string{"example.org"}
+	sixth, err := g.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := tls.LoadX509KeyPair(g.CAFile(), filepath.Join(dir, "ca-key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sixth.Leaf.NotAfter.Equal(caCert.Leaf.NotAfter) {
+		t.Fatalf("want %v, got %v", caCert.Leaf.NotAfter, sixth.Leaf.NotAfter)
+	}
+
+	// no temporary file is left
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("unexpected files %v", entries)
+	}
+}
+
+func TestCertGeneratorNoDir(t *testing.T) {
+	g := &netflag.CertGenerator{Name: "example", SANs: []string{"example", "::1"}}
+	cert, err := g.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"example"}, leaf.DNSNames); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if len(leaf.IPAddresses) != 1 || !leaf.IPAddresses[0].Equal(net.ParseIP("::1")) {
+		t.Errorf("unexpected IP addresses %v", leaf.IPAddresses)
+	}
+}
+
+func TestServerGenCertDir(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+
+	clientCfg := func() *tls.Config {
+		cfg, err := testClientTLSConfig("--tls-ca", caFile, "--tls-server-name", "localhost")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+	handshake := func(serverCfg, clientCfg *tls.Config) error {
+		c, s := net.Pipe()
+		defer c.Close()
+		defer s.Close()
+		go func() { _ = tls.Server(s, serverCfg).Handshake() }()
+		return tls.Client(c, clientCfg).Handshake()
+	}
+
+	args := []string{
+		"--tls-gen-cert",
+		"--tls-gen-cert-dir", dir,
+		"--tls-gen-cert-san", "localhost",
+	}
+	serverCfg, err := testServerTLSConfig(context.Background(), args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trusted := clientCfg()
+	if err := handshake(serverCfg, trusted); err != nil {
+		t.Fatal(err)
+	}
+
+	// restarted with the renewal
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	serverCfg, err = testServerTLSConfig(ctx, append(args, "--tls-reload-interval", "1h")...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(serverCfg, trusted); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := testServerTLSConfig(context.Background(), "--tls-gen-cert-dir", dir); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestServerGenCertDirCARenewal(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+
+	events := make(chan netflag.ReloadEvent, 10)
+	server := netflag.NewServer(clix.FlagPrefix("NETFLAG_TEST_"))
+	server.OnTLSReload = func(e netflag.ReloadEvent) { events <- e }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app := cli.NewApp()
+	app.Flags = server.Flags()
+	app.Before = server.Before
+	app.Action = func(c *cli.Context) error {
+		_, err := server.TLSConfigContext(ctx)
+		return err
+	}
+	err := app.Run([]string{"test", "--addr", "localhost:0",
+		"--tls-gen-cert",
+		"--tls-gen-cert-dir", dir,
+		"--tls-reload-interval", "10ms",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(caFile); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-events:
+		if e.Err != nil {
+			t.Fatal(e.Err)
+		}
+		if len(e.Files) == 0 || e.Files[0] != caFile {
+			t.Fatalf("want the CA in the event, got %v", e.Files)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+}
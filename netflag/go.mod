@@ -1,6 +1,6 @@
 module github.com/takumakei/go-urfave-cli/netflag
 
-go 1.16
+go 1.24
 
 require (
 	github.com/google/go-cmp v0.5.5
@@ -8,7 +8,21 @@ require (
 	github.com/takumakei/go-delint v0.0.0-20210515134037-9bbafd7915e7
 	github.com/takumakei/go-exit v0.0.0-20210515134037-47cd478cc20b
 	github.com/takumakei/go-stringx v0.0.0-20210515134037-56900ce87249
-	github.com/takumakei/go-urfave-cli/clix v0.0.0-20210517041205-ce5cefab2512
+	github.com/takumakei/go-urfave-cli/clix v0.1.0
 	github.com/takumakei/go-urfave-cli/fish v0.0.0-20210515134036-5c7785582e70
 	github.com/urfave/cli/v2 v2.3.0
+	golang.org/x/crypto v0.9.0
 )
+
+require (
+	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
+	github.com/google/renameio v1.0.1 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	gopkg.in/yaml.v2 v2.2.3 // indirect
+)
+
+// The clix APIs used here (Validator, Constraint and Errors) are released in
+// clix/v0.1.0, which must be tagged before this module. The replace directive
+// only applies to the builds in this repository.
+replace github.com/takumakei/go-urfave-cli/clix => ../clix
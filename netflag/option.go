@@ -3,8 +3,10 @@ package netflag
 import (
 	"crypto/tls"
 	"strings"
+	"time"
 
 	"github.com/takumakei/go-stringx"
+	"github.com/takumakei/go-urfave-cli/clix"
 )
 
 // config represents a state of options.
@@ -25,8 +27,21 @@ type config struct {
 	tlsMinVersion uint16
 	tlsMaxVersion uint16
 
+	tlsCipherSuites []uint16
+	tlsCurves       []tls.CurveID
+	tlsNextProtos   []string
+
+	tlsReloadInterval time.Duration
+
+	tlsClientAuth    tls.ClientAuthType
+	tlsClientAuthSet bool
+
 	genCertDisabled bool
+	genCertDir      string
+	genCertSANs     []string
 	tlsDisabled     bool
+
+	naming clix.Naming
 }
 
 func newConfig(opts ...Option) config {
@@ -86,6 +101,21 @@ func (c *config) addressRequired() bool {
 	return len(c.address) == 0
 }
 
+// flagName returns the name of the flag by c.naming with prefix.
+func (c *config) flagName(prefix clix.FlagPrefix, group, name, alias string) *clix.FlagName {
+	n := c.naming
+	n.Prefix = prefix
+	return n.NewFlagNameDefault(group, name, alias)
+}
+
+// tlsClientAuthValue returns the initial Value of FlagTLSClientAuth.
+func (c *config) tlsClientAuthValue() *TLSClientAuth {
+	if c.tlsClientAuthSet {
+		return NewTLSClientAuth(c.tlsClientAuth)
+	}
+	return &TLSClientAuth{}
+}
+
 // Option represents options for Client and Server.
 type Option func(*config)
 
@@ -107,6 +137,17 @@ func Network(net ...string) Option {
 	}
 }
 
+// Naming returns the option to name the flags by n, so that the default
+// files, the policy of the environment variables and the strategy of the
+// aliases of n are used. The Prefix of n is replaced by the prefix given to
+// the constructor. The aliases of the flags are made by n.Alias if it is not
+// nil.
+func Naming(n clix.Naming) Option {
+	return func(c *config) {
+		c.naming = n
+	}
+}
+
 // SkipVerify returns the option to set default value of FlagSkipVerify.
 func SkipVerify(v bool) Option {
 	return func(c *config) {
@@ -128,6 +169,44 @@ func TLSMaxVersion(v uint16) Option {
 	}
 }
 
+// CipherSuites returns the option to set default value of
+// FlagTLSCipherSuites.
+func CipherSuites(ids ...uint16) Option {
+	return func(c *config) {
+		c.tlsCipherSuites = ids
+	}
+}
+
+// CurvePreferences returns the option to set default value of FlagTLSCurves.
+func CurvePreferences(ids ...tls.CurveID) Option {
+	return func(c *config) {
+		c.tlsCurves = ids
+	}
+}
+
+// NextProtos returns the option to set default value of FlagTLSNextProtos.
+func NextProtos(protos ...string) Option {
+	return func(c *config) {
+		c.tlsNextProtos = protos
+	}
+}
+
+// TLSReloadInterval returns the option to set default value of FlagTLSReload.
+func TLSReloadInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.tlsReloadInterval = d
+	}
+}
+
+// TLSClientAuthPolicy returns the option to set default value of
+// FlagTLSClientAuth.
+func TLSClientAuthPolicy(auth tls.ClientAuthType) Option {
+	return func(c *config) {
+		c.tlsClientAuth = auth
+		c.tlsClientAuthSet = true
+	}
+}
+
 // GenCert returns the option whether using self-signed certificate.
 func GenCert(v bool) Option {
 	if v {
@@ -136,6 +215,20 @@ func GenCert(v bool) Option {
 	return DisableGenCert
 }
 
+// GenCertDir returns the option to set default value of FlagTLSGenCertDir.
+func GenCertDir(dir string) Option {
+	return func(c *config) {
+		c.genCertDir = dir
+	}
+}
+
+// GenCertSANs returns the option to set default value of FlagTLSGenCertSANs.
+func GenCertSANs(sans ...string) Option {
+	return func(c *config) {
+		c.genCertSANs = sans
+	}
+}
+
 // EnableGenCert is the option to use FlagTLSGenCert.
 func EnableGenCert(c *config) {
 	c.genCertDisabled = false
@@ -1,9 +1,12 @@
 package netflag
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
 )
 
 func TestAddress(t *testing.T) {
@@ -63,3 +66,45 @@ func TestNetwork(t *testing.T) {
 		}
 	}
 }
+
+func TestNaming(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app", "defaults", "api-address")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("localhost:80"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	n := clix.Naming{
+		Prefix:       "IGNORED_",
+		DefaultFiles: &clix.DefaultFiles{App: "app", Dirs: []string{dir}},
+	}
+	server := NewServerName("APP_", "api", Naming(n))
+	if diff := cmp.Diff(file, server.FlagAddress.FilePath); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"APP_API_ADDRESS", "APP_API_ADDR"}, server.FlagAddress.EnvVars); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+
+	client := NewClientName("APP_", "api", Naming(clix.Naming{Alias: clix.AliasNone}))
+	if len(client.FlagAddress.Aliases) != 0 {
+		t.Errorf("Aliases=%v, want none", client.FlagAddress.Aliases)
+	}
+}
+
+func TestNaming_env(t *testing.T) {
+	n := clix.Naming{Env: clix.EnvPolicy{LongNameOnly: true, GroupSeparator: "__", NoFile: true}}
+	server := NewServerName("APP__", "server", Naming(n))
+	if diff := cmp.Diff([]string{"APP__SERVER__ADDRESS"}, server.FlagAddress.EnvVars); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"server-addr"}, server.FlagAddress.Aliases); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if len(server.FlagAddress.FilePath) != 0 {
+		t.Errorf("FilePath=%q, want empty", server.FlagAddress.FilePath)
+	}
+}
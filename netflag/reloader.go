@@ -0,0 +1,178 @@
+package netflag
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReloadEvent represents the result of reloading the files.
+type ReloadEvent struct {
+	// Files is the files reloaded.
+	Files []string
+
+	// Err is non-nil if the files failed to load.
+	// The previously loaded ones are kept in use.
+	Err error
+}
+
+// CertReloader holds the pairs of the certificate and the private key loaded
+// from the files, and reloads them when the files change.
+type CertReloader struct {
+	certFiles []string
+	keyFiles  []string
+	onReload  func(ReloadEvent)
+
+	// certs is []tls.Certificate.
+	certs atomic.Value
+
+	mu     sync.Mutex
+	digest []byte
+}
+
+// NewCertReloader returns *CertReloader with the pairs loaded from certFiles
+// and keyFiles. onReload is called with the result of each reload after the
+// first load, may be nil.
+func NewCertReloader(certFiles, keyFiles []string, onReload func(ReloadEvent)) (*CertReloader, error) {
+	if len(certFiles) != len(keyFiles) {
+		if len(certFiles) < len(keyFiles) {
+			return nil, fmt.Errorf("no certificate file for private key")
+		}
+		return nil, fmt.Errorf("no key file for certificate")
+	}
+	if len(certFiles) == 0 {
+		return nil, errors.New("no certificate file")
+	}
+	r := &CertReloader{
+		certFiles: certFiles,
+		keyFiles:  keyFiles,
+		onReload:  onReload,
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.digest = digestFiles(r.certFiles, r.keyFiles)
+	certs, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.certs.Store(certs)
+	return r, nil
+}
+
+// Certificates returns the pairs in use.
+func (r *CertReloader) Certificates() []tls.Certificate {
+	return r.certs.Load().([]tls.Certificate)
+}
+
+// GetCertificate returns the first pair that supports hello,
+// or the first pair if none supports it.
+// GetCertificate is intended to be used as tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	certs := r.Certificates()
+	if len(certs) > 1 {
+		for i := range certs {
+			if hello.SupportsCertificate(&certs[i]) == nil {
+				return &certs[i], nil
+			}
+		}
+	}
+	return &certs[0], nil
+}
+
+// Reload loads the files, replaces the pairs in use if all of them are
+// valid, and calls onReload with the result.
+func (r *CertReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.digest = digestFiles(r.certFiles, r.keyFiles)
+	return r.reload()
+}
+
+// ReloadIfChanged calls Reload if the content of any file has changed since
+// the last load. The files that failed to load are not retried until they
+// change again.
+func (r *CertReloader) ReloadIfChanged() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	digest := digestFiles(r.certFiles, r.keyFiles)
+	if string(digest) == string(r.digest) {
+		return nil
+	}
+	r.digest = digest
+	return r.reload()
+}
+
+// Watch calls ReloadIfChanged every interval until ctx is done.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	watch(ctx, interval, r.ReloadIfChanged)
+}
+
+// reload loads the files, replaces the pairs in use on success,
+// and calls onReload.
+func (r *CertReloader) reload() error {
+	certs, err := r.load()
+	if err == nil {
+		r.certs.Store(certs)
+	}
+	if r.onReload != nil {
+		files := append(append([]string(nil), r.certFiles...), r.keyFiles...)
+		r.onReload(ReloadEvent{Files: files, Err: err})
+	}
+	return err
+}
+
+// load returns the pairs loaded from the files.
+func (r *CertReloader) load() ([]tls.Certificate, error) {
+	certs := make([]tls.Certificate, len(r.certFiles))
+	for i := range r.certFiles {
+		cert, err := tls.LoadX509KeyPair(r.certFiles[i], r.keyFiles[i])
+		if err != nil {
+			return nil, err
+		}
+		// Leaf makes ClientHelloInfo.SupportsCertificate cheap.
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, err
+		}
+		certs[i] = cert
+	}
+	return certs, nil
+}
+
+// watch calls reload every interval until ctx is done.
+// The errors are reported through onReload of the reloaders.
+func watch(ctx context.Context, interval time.Duration, reload func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = reload()
+		}
+	}
+}
+
+// digestFiles returns the digest of the contents of the files.
+// The error of reading a file is digested instead of its content.
+func digestFiles(files ...[]string) []byte {
+	h := sha256.New()
+	for _, list := range files {
+		for _, file := range list {
+			p, err := os.ReadFile(file)
+			if err != nil {
+				p = []byte(err.Error())
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", file, len(p))
+			h.Write(p)
+		}
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,139 @@
+package netflag_test
+
+import (
+	"crypto/elliptic"
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-cert4now"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/takumakei/go-urfave-cli/netflag"
+	"github.com/urfave/cli/v2"
+)
+
+func writeTestCert(t *testing.T, certFile, keyFile, name string) {
+	t.Helper()
+	cert, err := cert4now.Generate(
+		cert4now.CommonName(name),
+		cert4now.ECDSA(elliptic.P256()),
+		cert4now.DNSNames(name),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cert4now.WriteCertificateFile(certFile, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cert4now.WritePrivateKeyFile(keyFile, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commonName(t *testing.T, r *netflag.CertReloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert.Leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, "first")
+
+	var events []netflag.ReloadEvent
+	r, err := netflag.NewCertReloader([]string{certFile}, []string{keyFile}, func(e netflag.ReloadEvent) {
+		events = append(events, e)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("first", commonName(t, r)); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+
+	// not changed
+	if err := r.ReloadIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("unexpected events %v", events)
+	}
+
+	// changed
+	writeTestCert(t, certFile, keyFile, "second")
+	if err := r.ReloadIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("second", commonName(t, r)); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+	if len(events) != 1 || events[0].Err != nil {
+		t.Fatalf("unexpected events %v", events)
+	}
+	if diff := cmp.Diff([]string{certFile, keyFile}, events[0].Files); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+
+	// broken, the previous pair is kept and reported once
+	if err := os.WriteFile(keyFile, []byte("broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReloadIfChanged(); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := r.ReloadIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("second", commonName(t, r)); diff != "" {
+		t.Fatalf("-want +got\n%s", diff)
+	}
+	if len(events) != 2 || events[1].Err == nil {
+		t.Fatalf("unexpected events %v", events)
+	}
+}
+
+func TestNewCertReloader(t *testing.T) {
+	if _, err := netflag.NewCertReloader([]string{"a"}, nil, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := netflag.NewCertReloader(nil, nil, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestServerTLSConfig_noWatch(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, "first")
+
+	server := netflag.NewServer(clix.FlagPrefix("NETFLAG_TEST_"))
+	app := cli.NewApp()
+	app.Flags = server.Flags()
+	app.Before = server.Before
+	var cfg *tls.Config
+	app.Action = func(c *cli.Context) (err error) {
+		cfg, err = server.TLSConfig()
+		return
+	}
+	args := []string{"test", "--addr", "localhost:0", "--tls-cert", certFile, "--tls-cert-key", keyFile}
+	if err := app.Run(args); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetCertificate != nil || len(cfg.Certificates) != 1 {
+		t.Fatalf("want the loaded certificate, got %d certificates", len(cfg.Certificates))
+	}
+
+	// the interval is never ignored silently.
+	if err := app.Run(append(args, "--tls-reload-interval", "10ms")); !errors.Is(err, netflag.ErrReloadWithoutContext) {
+		t.Fatalf("got %v, want %v", err, netflag.ErrReloadWithoutContext)
+	}
+}
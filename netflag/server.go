@@ -1,15 +1,15 @@
 package netflag
 
 import (
-	"crypto/elliptic"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
 	"os"
+	"time"
 
-	"github.com/takumakei/go-cert4now"
-	"github.com/takumakei/go-delint"
 	"github.com/takumakei/go-urfave-cli/clix"
 	"github.com/urfave/cli/v2"
 )
@@ -43,17 +43,58 @@ type Server struct {
 	// FlagTLSGenCert specifies whether to generate a self signed certificate.
 	FlagTLSGenCert *cli.BoolFlag
 
+	// FlagTLSGenCertDir is the directory to keep the generated CA and
+	// certificate.
+	FlagTLSGenCertDir *cli.StringFlag
+
+	// FlagTLSGenCertSANs is the DNS names and the IP addresses of the generated
+	// certificate.
+	FlagTLSGenCertSANs *cli.StringSliceFlag
+
 	// FlagTLSCAs is the certificate filepath of the ClientCAs.
 	FlagTLSCAs *cli.StringSliceFlag
 
+	// FlagTLSClientAuth is the policy of client auth.
+	FlagTLSClientAuth *cli.GenericFlag
+
+	// FlagTLSClientAllow is the names of the client certificates that are allowed.
+	FlagTLSClientAllow *cli.StringSliceFlag
+
+	// FlagTLSCRLs is the certificate revocation list filepath for client auth.
+	FlagTLSCRLs *cli.StringSliceFlag
+
+	// FlagTLSOCSP is the URL of the OCSP responder for client auth.
+	FlagTLSOCSP *cli.StringFlag
+
+	// FlagTLSOCSPSoftFail accepts the client certificates when the OCSP
+	// responder is unavailable.
+	FlagTLSOCSPSoftFail *cli.BoolFlag
+
 	// FlagTLSMinVer is the minimum TLS version that is acceptable.
 	FlagTLSMinVer *cli.GenericFlag
 
 	// FlagTLSMaxVer is the maximum TLS version that is acceptable.
 	FlagTLSMaxVer *cli.GenericFlag
 
+	// FlagTLSCipherSuites is the cipher suites of TLS 1.0-1.2 that are acceptable.
+	FlagTLSCipherSuites *cli.GenericFlag
+
+	// FlagTLSCurves is the elliptic curves in preference order.
+	FlagTLSCurves *cli.GenericFlag
+
+	// FlagTLSNextProtos is the ALPN protocols in preference order.
+	FlagTLSNextProtos *cli.GenericFlag
+
+	// FlagTLSReload is the interval to check the certificate, CA and CRL files
+	// for changes.
+	FlagTLSReload *cli.DurationFlag
+
 	// FlagSet is clix.FlagSet.
 	FlagSet clix.FlagSet
+
+	// OnTLSReload is called with the result of reloading the certificate
+	// files, may be nil.
+	OnTLSReload func(ReloadEvent)
 }
 
 // NewServer returns NewServer(prefix, "", opts...).
@@ -66,14 +107,25 @@ func NewServerName(prefix clix.FlagPrefix, name string, opts ...Option) *Server
 	cfg := newConfig(opts...)
 
 	var (
-		nameNetwork    = clix.NewFlagNameAlias(prefix, name, "network", "net")
-		nameAddress    = clix.NewFlagNameAlias(prefix, name, "address", "addr")
-		nameTLSCert    = clix.NewFlagNameAlias(prefix, name, "tls-cert", "tlscrt")
-		nameTLSKey     = clix.NewFlagNameAlias(prefix, name, "tls-cert-key", "tlskey")
-		nameTLSGenCert = clix.NewFlagNameAlias(prefix, name, "tls-gen-cert", "tlsgen")
-		nameTLSCAs     = clix.NewFlagNameAlias(prefix, name, "tls-ca", "tlsca")
-		nameTLSMinVer  = clix.NewFlagNameAlias(prefix, name, "tls-min-version", "tlsmin")
-		nameTLSMaxVer  = clix.NewFlagNameAlias(prefix, name, "tls-max-version", "tlsmax")
+		nameNetwork    = cfg.flagName(prefix, name, "network", "net")
+		nameAddress    = cfg.flagName(prefix, name, "address", "addr")
+		nameTLSCert    = cfg.flagName(prefix, name, "tls-cert", "tlscrt")
+		nameTLSKey     = cfg.flagName(prefix, name, "tls-cert-key", "tlskey")
+		nameTLSGenCert = cfg.flagName(prefix, name, "tls-gen-cert", "tlsgen")
+		nameTLSGenDir  = cfg.flagName(prefix, name, "tls-gen-cert-dir", "tlsgendir")
+		nameTLSGenSANs = cfg.flagName(prefix, name, "tls-gen-cert-san", "tlsgensan")
+		nameTLSCAs     = cfg.flagName(prefix, name, "tls-ca", "tlsca")
+		nameTLSAuth    = cfg.flagName(prefix, name, "tls-client-auth", "tlsauth")
+		nameTLSAllow   = cfg.flagName(prefix, name, "tls-client-allow", "tlsallow")
+		nameTLSCRLs    = cfg.flagName(prefix, name, "tls-crl", "tlscrl")
+		nameTLSOCSP    = cfg.flagName(prefix, name, "tls-ocsp-url", "tlsocsp")
+		nameTLSOCSPSF  = cfg.flagName(prefix, name, "tls-ocsp-soft-fail", "tlsocspsoft")
+		nameTLSMinVer  = cfg.flagName(prefix, name, "tls-min-version", "tlsmin")
+		nameTLSMaxVer  = cfg.flagName(prefix, name, "tls-max-version", "tlsmax")
+		nameTLSCiphers = cfg.flagName(prefix, name, "tls-cipher-suites", "tlsciphers")
+		nameTLSCurves  = cfg.flagName(prefix, name, "tls-curves", "tlscurves")
+		nameTLSALPN    = cfg.flagName(prefix, name, "tls-alpn", "tlsalpn")
+		nameTLSReload  = cfg.flagName(prefix, name, "tls-reload-interval", "tlsreload")
 	)
 
 	network := cfg.networkValue()
@@ -146,6 +198,27 @@ func NewServerName(prefix clix.FlagPrefix, name string, opts ...Option) *Server
 
 		FlagTLSGenCert: flagTLSGenCert,
 
+		FlagTLSGenCertDir: &cli.StringFlag{
+			Name:        nameTLSGenDir.Name,
+			Aliases:     nameTLSGenDir.Aliases,
+			Usage:       "`directory` to keep the generated CA (ca.pem to be trusted by clients) and certificate",
+			EnvVars:     nameTLSGenDir.EnvVars,
+			FilePath:    nameTLSGenDir.FilePath,
+			TakesFile:   true,
+			Value:       cfg.genCertDir,
+			Destination: new(string),
+		},
+
+		FlagTLSGenCertSANs: &cli.StringSliceFlag{
+			Name:        nameTLSGenSANs.Name,
+			Aliases:     nameTLSGenSANs.Aliases,
+			Usage:       "DNS `name` or IP address of the generated certificate, the name of the server and the hostname by default",
+			EnvVars:     nameTLSGenSANs.EnvVars,
+			FilePath:    nameTLSGenSANs.FilePath,
+			Value:       cli.NewStringSlice(cfg.genCertSANs...),
+			Destination: &cli.StringSlice{},
+		},
+
 		FlagTLSCAs: &cli.StringSliceFlag{
 			Name:        nameTLSCAs.Name,
 			Aliases:     nameTLSCAs.Aliases,
@@ -156,6 +229,52 @@ func NewServerName(prefix clix.FlagPrefix, name string, opts ...Option) *Server
 			Destination: &cli.StringSlice{},
 		},
 
+		FlagTLSClientAuth: &cli.GenericFlag{
+			Name:     nameTLSAuth.Name,
+			Aliases:  nameTLSAuth.Aliases,
+			Usage:    "client auth `policy` [none|request|require|verify-if-given|require-and-verify], require-and-verify if CA is given, otherwise none by default",
+			EnvVars:  nameTLSAuth.EnvVars,
+			FilePath: nameTLSAuth.FilePath,
+			Value:    cfg.tlsClientAuthValue(),
+		},
+
+		FlagTLSClientAllow: &cli.StringSliceFlag{
+			Name:        nameTLSAllow.Name,
+			Aliases:     nameTLSAllow.Aliases,
+			Usage:       "allowed `name` of client certificates, CN, DN, SAN or SPIFFE ID",
+			EnvVars:     nameTLSAllow.EnvVars,
+			FilePath:    nameTLSAllow.FilePath,
+			Destination: &cli.StringSlice{},
+		},
+
+		FlagTLSCRLs: &cli.StringSliceFlag{
+			Name:        nameTLSCRLs.Name,
+			Aliases:     nameTLSCRLs.Aliases,
+			Usage:       "certificate revocation list `file` for client auth",
+			EnvVars:     nameTLSCRLs.EnvVars,
+			FilePath:    nameTLSCRLs.FilePath,
+			TakesFile:   true,
+			Destination: &cli.StringSlice{},
+		},
+
+		FlagTLSOCSP: &cli.StringFlag{
+			Name:        nameTLSOCSP.Name,
+			Aliases:     nameTLSOCSP.Aliases,
+			Usage:       "OCSP responder `URL` for client auth",
+			EnvVars:     nameTLSOCSP.EnvVars,
+			FilePath:    nameTLSOCSP.FilePath,
+			Destination: new(string),
+		},
+
+		FlagTLSOCSPSoftFail: &cli.BoolFlag{
+			Name:        nameTLSOCSPSF.Name,
+			Aliases:     nameTLSOCSPSF.Aliases,
+			Usage:       "accept client certificates when the OCSP responder is unavailable",
+			EnvVars:     nameTLSOCSPSF.EnvVars,
+			FilePath:    nameTLSOCSPSF.FilePath,
+			Destination: new(bool),
+		},
+
 		FlagTLSMinVer: &cli.GenericFlag{
 			Name:     nameTLSMinVer.Name,
 			Aliases:  nameTLSMinVer.Aliases,
@@ -174,29 +293,86 @@ func NewServerName(prefix clix.FlagPrefix, name string, opts ...Option) *Server
 			Value:    NewTLSVersion(cfg.tlsMaxVersion),
 		},
 
+		FlagTLSCipherSuites: &cli.GenericFlag{
+			Name:     nameTLSCiphers.Name,
+			Aliases:  nameTLSCiphers.Aliases,
+			Usage:    "comma separated IANA `names` of TLS 1.0-1.2 cipher suites",
+			EnvVars:  nameTLSCiphers.EnvVars,
+			FilePath: nameTLSCiphers.FilePath,
+			Value:    NewTLSCipherSuites(cfg.tlsCipherSuites...),
+		},
+
+		FlagTLSCurves: &cli.GenericFlag{
+			Name:     nameTLSCurves.Name,
+			Aliases:  nameTLSCurves.Aliases,
+			Usage:    "comma separated elliptic `curves` in preference order [X25519|P-256|P-384|P-521|X25519MLKEM768]",
+			EnvVars:  nameTLSCurves.EnvVars,
+			FilePath: nameTLSCurves.FilePath,
+			Value:    NewTLSCurves(cfg.tlsCurves...),
+		},
+
+		FlagTLSNextProtos: &cli.GenericFlag{
+			Name:     nameTLSALPN.Name,
+			Aliases:  nameTLSALPN.Aliases,
+			Usage:    "comma separated ALPN `protocols` in preference order",
+			EnvVars:  nameTLSALPN.EnvVars,
+			FilePath: nameTLSALPN.FilePath,
+			Value:    NewTLSNextProtos(cfg.tlsNextProtos...),
+		},
+
+		FlagTLSReload: &cli.DurationFlag{
+			Name:        nameTLSReload.Name,
+			Aliases:     nameTLSReload.Aliases,
+			Usage:       "interval to reload the certificate, CA and CRL files on change, 0 to disable",
+			EnvVars:     nameTLSReload.EnvVars,
+			FilePath:    nameTLSReload.FilePath,
+			Value:       cfg.tlsReloadInterval,
+			Destination: new(time.Duration),
+		},
+
 		FlagSet: flagSet,
 	}
 }
 
-// Before calls f.FlagSet.Init(c), validates exclusive flags.
+var _ clix.Validator = (*Server)(nil)
+
+// Validate calls f.FlagSet.Init(c), then checks all of f.Constraints(),
+// returns all the violations together as clix.Errors.
+func (f *Server) Validate(c *cli.Context) error {
+	return f.FlagSet.Validate(f.Constraints()...)(c)
+}
+
+// Before calls f.Validate(c).
 // Before is intended to be used as cli.BeforeFunc.
 func (f *Server) Before(c *cli.Context) error {
-	delint.Must(f.FlagSet.Init(c))
-	if f.FlagSet.IsSet(f.FlagTLSCerts) && f.TLSGenCert() {
-		return fmt.Errorf(
-			"%q and %q must not set at the same time",
-			f.FlagTLSCerts.Name,
-			f.FlagTLSGenCert.Name,
-		)
+	return f.Validate(c)
+}
+
+// Constraints returns the constraints checked by Validate.
+func (f *Server) Constraints() []clix.Constraint {
+	genCert := func(clix.FlagSet) bool { return f.TLSGenCert() }
+	noGenCert := func(clix.FlagSet) bool { return !f.TLSGenCert() }
+	verifies := func(clix.FlagSet) bool { return verifiesClientCert(f.TLSClientAuth()) }
+	noVerifies := func(clix.FlagSet) bool { return !verifiesClientCert(f.TLSClientAuth()) }
+	genCertName := "--" + f.FlagTLSGenCert.Name
+	authName := "--" + f.FlagTLSClientAuth.Name
+	constraints := []clix.Constraint{
+		clix.ForbiddenIf(genCertName+" is true", genCert, f.FlagTLSCerts, f.FlagTLSKeys),
+		clix.ForbiddenIf(genCertName+" is false", noGenCert, f.FlagTLSGenCertDir, f.FlagTLSGenCertSANs),
+		clix.Requires(f.FlagTLSCRLs, f.FlagTLSCAs),
+		clix.Requires(f.FlagTLSOCSP, f.FlagTLSCAs),
+		clix.Requires(f.FlagTLSOCSPSoftFail, f.FlagTLSOCSP),
+		clix.Requires(f.FlagTLSClientAllow, f.FlagTLSCAs),
+		clix.RequiredIf(authName+" verifies client certificates", verifies, f.FlagTLSCAs),
+		clix.ForbiddenIf(authName+" does not verify client certificates", noVerifies, f.FlagTLSClientAllow),
+		checkKeyPairs(f.FlagTLSCerts, f.FlagTLSKeys),
+		checkPort(f.FlagSet, f.Network, f.FlagAddress),
 	}
-	if f.FlagSet.IsSet(f.FlagTLSKeys) && f.TLSGenCert() {
-		return fmt.Errorf(
-			"%q and %q must not set at the same time",
-			f.FlagTLSKeys.Name,
-			f.FlagTLSGenCert.Name,
-		)
+	constraints = append(constraints, checkFilesExist(f.FlagSet, f.FlagTLSCerts, f.FlagTLSKeys, f.FlagTLSCAs, f.FlagTLSCRLs)...)
+	if !f.DisableTLS {
+		constraints = append(constraints, checkTLSFlags(f.FlagTLSMinVer, f.FlagTLSMaxVer, f.FlagTLSCipherSuites, f.FlagTLSCurves))
 	}
-	return nil
+	return constraints
 }
 
 // Flags returns []cli.Flag.
@@ -211,9 +387,20 @@ func (f *Server) Before(c *cli.Context) error {
 //     f.FlagTLSCerts
 //     f.FlagTLSKeys
 //     f.FlagTLSGenCert  (if not disabled)
+//     f.FlagTLSGenCertDir  (if not disabled)
+//     f.FlagTLSGenCertSANs  (if not disabled)
 //     f.FlagTLSCAs
+//     f.FlagTLSClientAuth
+//     f.FlagTLSClientAllow
+//     f.FlagTLSCRLs
+//     f.FlagTLSOCSP
+//     f.FlagTLSOCSPSoftFail
 //     f.FlagTLSMinVer
 //     f.FlagTLSMaxVer
+//     f.FlagTLSCipherSuites
+//     f.FlagTLSCurves
+//     f.FlagTLSNextProtos
+//     f.FlagTLSReload
 func (f *Server) Flags() []cli.Flag {
 	return clix.Flags(
 		clix.FlagIf(!f.PredeterminedFlagNetwork, f.FlagNetwork),
@@ -221,10 +408,19 @@ func (f *Server) Flags() []cli.Flag {
 		clix.FlagIf(!f.DisableTLS, clix.Flags(
 			f.FlagTLSCerts,
 			f.FlagTLSKeys,
-			clix.FlagIf(!f.DisableFlagTLSGenCert, f.FlagTLSGenCert),
+			clix.FlagIf(!f.DisableFlagTLSGenCert, f.FlagTLSGenCert, f.FlagTLSGenCertDir, f.FlagTLSGenCertSANs),
 			f.FlagTLSCAs,
+			f.FlagTLSClientAuth,
+			f.FlagTLSClientAllow,
+			f.FlagTLSCRLs,
+			f.FlagTLSOCSP,
+			f.FlagTLSOCSPSoftFail,
 			f.FlagTLSMinVer,
 			f.FlagTLSMaxVer,
+			f.FlagTLSCipherSuites,
+			f.FlagTLSCurves,
+			f.FlagTLSNextProtos,
+			f.FlagTLSReload,
 		)...),
 	)
 }
@@ -254,11 +450,63 @@ func (f *Server) TLSGenCert() bool {
 	return *f.FlagTLSGenCert.Destination
 }
 
+// TLSGenCertDir returns the value of FlagTLSGenCertDir.
+func (f *Server) TLSGenCertDir() string {
+	return *f.FlagTLSGenCertDir.Destination
+}
+
+// TLSGenCertSANs returns the value of FlagTLSGenCertSANs.
+func (f *Server) TLSGenCertSANs() []string {
+	return f.FlagTLSGenCertSANs.Destination.Value()
+}
+
+// TLSCertGenerator returns *CertGenerator for FlagTLSGenCert.
+// The SANs are f.Name and the hostname if FlagTLSGenCertSANs is empty.
+func (f *Server) TLSCertGenerator() *CertGenerator {
+	sans := f.TLSGenCertSANs()
+	if len(sans) == 0 {
+		hostname, _ := os.Hostname()
+		sans = []string{f.Name, hostname}
+	}
+	return &CertGenerator{
+		Name: f.Name,
+		SANs: sans,
+		Dir:  f.TLSGenCertDir(),
+	}
+}
+
 // TLSCAs returns the value of FlagTLSCAs.
 func (f *Server) TLSCAs() []string {
 	return f.FlagTLSCAs.Destination.Value()
 }
 
+// TLSClientAuth returns the value of FlagTLSClientAuth.
+// It is tls.RequireAndVerifyClientCert if FlagTLSCAs is given, otherwise
+// tls.NoClientCert by default.
+func (f *Server) TLSClientAuth() tls.ClientAuthType {
+	return f.FlagTLSClientAuth.Value.(*TLSClientAuth).Value(len(f.TLSCAs()) > 0)
+}
+
+// TLSClientAllow returns the value of FlagTLSClientAllow.
+func (f *Server) TLSClientAllow() ClientAllowList {
+	return f.FlagTLSClientAllow.Destination.Value()
+}
+
+// TLSCRLs returns the value of FlagTLSCRLs.
+func (f *Server) TLSCRLs() []string {
+	return f.FlagTLSCRLs.Destination.Value()
+}
+
+// TLSOCSPURL returns the value of FlagTLSOCSP.
+func (f *Server) TLSOCSPURL() string {
+	return *f.FlagTLSOCSP.Destination
+}
+
+// TLSOCSPSoftFail returns the value of FlagTLSOCSPSoftFail.
+func (f *Server) TLSOCSPSoftFail() bool {
+	return *f.FlagTLSOCSPSoftFail.Destination
+}
+
 // TLSMinVersion returns the value of FlagTLSMinVer.
 func (f *Server) TLSMinVersion() uint16 {
 	return f.FlagTLSMinVer.Value.(*TLSVersion).Value()
@@ -269,15 +517,43 @@ func (f *Server) TLSMaxVersion() uint16 {
 	return f.FlagTLSMaxVer.Value.(*TLSVersion).Value()
 }
 
+// TLSCipherSuites returns the value of FlagTLSCipherSuites.
+func (f *Server) TLSCipherSuites() []uint16 {
+	return f.FlagTLSCipherSuites.Value.(*TLSCipherSuites).Value()
+}
+
+// TLSCurvePreferences returns the value of FlagTLSCurves.
+func (f *Server) TLSCurvePreferences() []tls.CurveID {
+	return f.FlagTLSCurves.Value.(*TLSCurves).Value()
+}
+
+// TLSNextProtos returns the value of FlagTLSNextProtos.
+func (f *Server) TLSNextProtos() []string {
+	return f.FlagTLSNextProtos.Value.(*TLSNextProtos).Value()
+}
+
+// TLSReloadInterval returns the value of FlagTLSReload.
+func (f *Server) TLSReloadInterval() time.Duration {
+	return *f.FlagTLSReload.Destination
+}
+
 // UseTLS returns true if TLS related flags are presented.
 func (f *Server) UseTLS() bool {
 	list := []cli.Flag{
 		f.FlagTLSCerts,
 		f.FlagTLSKeys,
 		f.FlagTLSCAs,
+		f.FlagTLSClientAuth,
+		f.FlagTLSClientAllow,
+		f.FlagTLSCRLs,
+		f.FlagTLSOCSP,
+		f.FlagTLSOCSPSoftFail,
 		f.FlagTLSGenCert,
 		f.FlagTLSMinVer,
 		f.FlagTLSMaxVer,
+		f.FlagTLSCipherSuites,
+		f.FlagTLSCurves,
+		f.FlagTLSNextProtos,
 	}
 	for _, flag := range list {
 		if f.FlagSet.IsSet(flag) {
@@ -287,21 +563,69 @@ func (f *Server) UseTLS() bool {
 	return false
 }
 
-// TLSConfig returns *tls.Config.
+// ErrReloadWithoutContext represents an error where the files are to be
+// reloaded without the context to stop watching them.
+var ErrReloadWithoutContext = errors.New("tls-reload-interval requires TLSConfigContext")
+
+// TLSConfig returns *tls.Config like f.TLSConfigContext, but the files are
+// loaded only once, since there is no context to stop watching them.
+// TLSConfig returns ErrReloadWithoutContext if f.TLSReloadInterval() is
+// positive. Use TLSConfigContext to reload the files.
 func (f *Server) TLSConfig() (*tls.Config, error) {
+	if f.TLSReloadInterval() > 0 {
+		return nil, ErrReloadWithoutContext
+	}
+	return f.tlsConfig(nil)
+}
+
+// TLSConfigContext returns *tls.Config.
+//
+// If f.TLSReloadInterval() is positive, the certificate files are reloaded
+// on change through GetCertificate, and the CA and CRL files through
+// GetConfigForClient, until ctx is done. The certificate generated in
+// f.TLSGenCertDir() is also renewed before expiry.
+func (f *Server) TLSConfigContext(ctx context.Context) (*tls.Config, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return f.tlsConfig(ctx)
+}
+
+// tlsConfig returns *tls.Config watching the files until ctx is done.
+// The files are not watched if ctx is nil.
+func (f *Server) tlsConfig(ctx context.Context) (*tls.Config, error) {
+	var interval time.Duration
+	if ctx != nil {
+		interval = f.TLSReloadInterval()
+	}
+	if len(f.TLSClientAllow()) > 0 && !verifiesClientCert(f.TLSClientAuth()) {
+		return nil, errors.New("client allow-list requires the client auth policy that verifies client certificates")
+	}
+
 	var certs []tls.Certificate
+	var getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
 	if f.TLSGenCert() {
-		hostname, _ := os.Hostname()
-		cert, err := cert4now.Generate(
-			cert4now.CommonName(f.Name),
-			cert4now.ECDSA(elliptic.P384()),
-			cert4now.AddDate(100, 0, 0),
-			cert4now.DNSNames(f.Name, hostname),
-		)
+		g := f.TLSCertGenerator()
+		if interval > 0 && len(g.Dir) > 0 {
+			var err error
+			getCertificate, err = g.watchCertificate(ctx, interval, f.OnTLSReload)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			cert, err := g.Certificate()
+			if err != nil {
+				return nil, err
+			}
+			certs = []tls.Certificate{cert}
+		}
+	} else if interval > 0 {
+		reloader, err := NewCertReloader(f.TLSCerts(), f.TLSKeys(), f.OnTLSReload)
 		if err != nil {
 			return nil, err
 		}
-		certs = []tls.Certificate{cert}
+		go reloader.Watch(ctx, interval)
+		getCertificate = reloader.GetCertificate
 	} else {
 		certFiles := f.TLSCerts()
 		keyFiles := f.TLSKeys()
@@ -320,28 +644,52 @@ func (f *Server) TLSConfig() (*tls.Config, error) {
 		}
 	}
 
-	clientAuth := tls.NoClientCert
-	var clientCAs *x509.CertPool
+	cfg := &tls.Config{
+		Certificates:     certs,
+		GetCertificate:   getCertificate,
+		ClientAuth:       f.TLSClientAuth(),
+		MinVersion:       f.TLSMinVersion(),
+		MaxVersion:       f.TLSMaxVersion(),
+		CipherSuites:     f.TLSCipherSuites(),
+		CurvePreferences: f.TLSCurvePreferences(),
+		NextProtos:       f.TLSNextProtos(),
+	}
+
 	if cas := f.TLSCAs(); len(cas) > 0 {
-		clientCAs = x509.NewCertPool()
-		for _, ca := range f.TLSCAs() {
-			p, err := os.ReadFile(ca)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read CA %q, %w", ca, err)
+		verifier, err := NewClientVerifier(cas, f.TLSCRLs(), f.TLSOCSPURL(), f.OnTLSReload)
+		if err != nil {
+			return nil, err
+		}
+		verifier.OCSPSoftFail = f.TLSOCSPSoftFail()
+		allow := f.TLSClientAllow()
+		verify := func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			if err := verifier.VerifyPeerCertificate(rawCerts, verifiedChains); err != nil {
+				return err
 			}
-			if ok := clientCAs.AppendCertsFromPEM(p); !ok {
-				return nil, fmt.Errorf("failed to load CA %q", ca)
+			if len(allow) > 0 {
+				return allow.VerifyPeerCertificate(rawCerts, verifiedChains)
+			}
+			return nil
+		}
+		cfg.ClientCAs = verifier.ClientCAs()
+		cfg.VerifyPeerCertificate = verify
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			// VerifyPeerCertificate is not called on the resumed connections.
+			if !cs.DidResume {
+				return nil
+			}
+			return verify(nil, cs.VerifiedChains)
+		}
+		if interval > 0 {
+			go verifier.Watch(ctx, interval)
+			base := cfg
+			cfg = base.Clone()
+			cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				c := base.Clone()
+				c.ClientCAs = verifier.ClientCAs()
+				return c, nil
 			}
 		}
-		clientAuth = tls.RequireAndVerifyClientCert
-	}
-
-	cfg := &tls.Config{
-		Certificates: certs,
-		ClientAuth:   clientAuth,
-		ClientCAs:    clientCAs,
-		MinVersion:   f.TLSMinVersion(),
-		MaxVersion:   f.TLSMaxVersion(),
 	}
 
 	return cfg, nil
@@ -352,13 +700,47 @@ func (f *Server) Listen() (net.Listener, error) {
 	return f.ListenNetwork(f.Network())
 }
 
-// ListenNetwork returns the result of calling tls.Listen if f.UseTLS() returns
-// true, otherwise returns the result of calling net.Listen.
-// f.Address() and f.TLSConfig() are used.
+// ListenNetwork returns the result of calling
+// f.ListenNetworkContext(ctx, network) with ctx that is done when the
+// listener is closed, so that the files are watched while it is open.
 func (f *Server) ListenNetwork(network string) (net.Listener, error) {
+	if !f.UseTLS() {
+		return net.Listen(network, f.Address())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	l, err := f.ListenNetworkContext(ctx, network)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &cancelListener{Listener: l, cancel: cancel}, nil
+}
+
+// cancelListener is a net.Listener that calls cancel on Close.
+type cancelListener struct {
+	net.Listener
+	cancel context.CancelFunc
+}
+
+// Close calls l.cancel and closes the listener.
+func (l *cancelListener) Close() error {
+	l.cancel()
+	return l.Listener.Close()
+}
+
+// ListenContext returns the result of calling
+// f.ListenNetworkContext(ctx, f.Network()).
+func (f *Server) ListenContext(ctx context.Context) (net.Listener, error) {
+	return f.ListenNetworkContext(ctx, f.Network())
+}
+
+// ListenNetworkContext returns the result of calling tls.Listen if f.UseTLS()
+// returns true, otherwise returns the result of calling net.Listen.
+// f.Address() and f.TLSConfigContext(ctx) are used.
+func (f *Server) ListenNetworkContext(ctx context.Context, network string) (net.Listener, error) {
 	a := f.Address()
 	if f.UseTLS() {
-		cfg, err := f.TLSConfig()
+		cfg, err := f.TLSConfigContext(ctx)
 		if err != nil {
 			return nil, err
 		}
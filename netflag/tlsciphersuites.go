@@ -0,0 +1,130 @@
+package netflag
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// TLSCipherSuites wraps a list of the IDs of the cipher suites to satisfy
+// flag.Value.
+//
+// The cipher suites of TLS 1.3 are not configurable, so that they are
+// rejected.
+type TLSCipherSuites struct {
+	ids []uint16
+}
+
+// Note: cli.Generic is equivalent to flag.Value.
+var _ cli.Generic = (*TLSCipherSuites)(nil)
+
+// NewTLSCipherSuites creates a *TLSCipherSuites with a default value.
+func NewTLSCipherSuites(value ...uint16) *TLSCipherSuites {
+	return &TLSCipherSuites{ids: value}
+}
+
+// Set parses value as the comma separated IANA names of the cipher suites,
+// replaces the value with them.
+func (cs *TLSCipherSuites) Set(value string) error {
+	var ids []uint16
+	for _, name := range splitList(value) {
+		suite, err := lookupCipherSuite(name)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, suite.ID)
+	}
+	cs.ids = ids
+	return nil
+}
+
+// lookupCipherSuite returns the cipher suite named name.
+func lookupCipherSuite(name string) (*tls.CipherSuite, error) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name != name {
+			continue
+		}
+		if isTLS13Only(suite) {
+			return nil, fmt.Errorf("%s is a cipher suite of TLS 1.3 that is not configurable", name)
+		}
+		return suite, nil
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return nil, fmt.Errorf("%s is an insecure cipher suite", name)
+		}
+	}
+	return nil, fmt.Errorf("%s is not a cipher suite", name)
+}
+
+// String returns a readable representation of this value (for usage defaults)
+func (cs *TLSCipherSuites) String() string {
+	names := make([]string, len(cs.ids))
+	for i, id := range cs.ids {
+		names[i] = tls.CipherSuiteName(id)
+	}
+	return strings.Join(names, ",")
+}
+
+// Value returns the IDs of the cipher suites set by this flag,
+// nil for the default of crypto/tls.
+func (cs *TLSCipherSuites) Value() []uint16 {
+	return cs.ids
+}
+
+// Validate returns non-nil error if any of the cipher suites supports none of
+// the TLS versions from min to max.
+func (cs *TLSCipherSuites) Validate(min, max uint16) error {
+	if len(cs.ids) == 0 {
+		return nil
+	}
+	if min >= tls.VersionTLS13 {
+		return fmt.Errorf("cipher suites are not configurable for TLS 1.3")
+	}
+	for _, id := range cs.ids {
+		if !supportsVersion(id, min, max) {
+			return fmt.Errorf("%s is not supported by TLS %s-%s",
+				tls.CipherSuiteName(id), NewTLSVersion(min), NewTLSVersion(max))
+		}
+	}
+	return nil
+}
+
+// isTLS13Only returns true if suite supports only TLS 1.3.
+func isTLS13Only(suite *tls.CipherSuite) bool {
+	for _, v := range suite.SupportedVersions {
+		if v != tls.VersionTLS13 {
+			return false
+		}
+	}
+	return true
+}
+
+// supportsVersion returns true if the cipher suite id supports any TLS
+// version from min to max.
+func supportsVersion(id uint16, min, max uint16) bool {
+	for _, suite := range tls.CipherSuites() {
+		if suite.ID != id {
+			continue
+		}
+		for _, v := range suite.SupportedVersions {
+			if min <= v && v <= max {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitList returns the non-empty elements of the comma separated value.
+func splitList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,168 @@
+package netflag_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/takumakei/go-urfave-cli/netflag"
+	"github.com/urfave/cli/v2"
+)
+
+func TestTLSCipherSuites(t *testing.T) {
+	v := netflag.NewTLSCipherSuites(tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256)
+	if diff := cmp.Diff("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", v.String()); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if err := v.Set("TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"); err != nil {
+		t.Fatal(err)
+	}
+	want := []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	}
+	if diff := cmp.Diff(want, v.Value()); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+
+	for _, name := range []string{"TLS_RSA_WITH_RC4_128_SHA", "TLS_AES_128_GCM_SHA256", "TLS_UNKNOWN"} {
+		if err := v.Set(name); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	cases := []struct {
+		Min, Max uint16
+		Suites   []uint16
+		OK       bool
+	}{
+		{tls.VersionTLS12, tls.VersionTLS13, nil, true},
+		{tls.VersionTLS13, tls.VersionTLS13, nil, true},
+		{tls.VersionTLS12, tls.VersionTLS13, want, true},
+		{tls.VersionTLS13, tls.VersionTLS13, want, false},
+		{tls.VersionTLS10, tls.VersionTLS11, want, false},
+		{tls.VersionTLS10, tls.VersionTLS11, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA}, true},
+	}
+	for i, c := range cases {
+		err := netflag.NewTLSCipherSuites(c.Suites...).Validate(c.Min, c.Max)
+		if (err == nil) != c.OK {
+			t.Errorf("#%d: unexpected result %v", i, err)
+		}
+	}
+}
+
+func TestTLSCurves(t *testing.T) {
+	v := netflag.NewTLSCurves()
+	if err := v.Set("x25519,P-256,CurveP384"); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}, v.Value()); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if diff := cmp.Diff("X25519,P-256,P-384", v.String()); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if err := v.Set("P-224"); err == nil {
+		t.Error("expected error")
+	}
+
+	cases := []struct {
+		Min, Max uint16
+		Curves   []tls.CurveID
+		OK       bool
+	}{
+		{tls.VersionTLS12, tls.VersionTLS13, nil, true},
+		{tls.VersionTLS12, tls.VersionTLS13, []tls.CurveID{tls.X25519MLKEM768, tls.X25519}, true},
+		{tls.VersionTLS13, tls.VersionTLS13, []tls.CurveID{tls.X25519MLKEM768}, true},
+		{tls.VersionTLS12, tls.VersionTLS13, []tls.CurveID{tls.X25519MLKEM768}, false},
+		{tls.VersionTLS12, tls.VersionTLS12, []tls.CurveID{tls.X25519MLKEM768, tls.X25519}, false},
+	}
+	for i, c := range cases {
+		err := netflag.NewTLSCurves(c.Curves...).Validate(c.Min, c.Max)
+		if (err == nil) != c.OK {
+			t.Errorf("#%d: unexpected result %v", i, err)
+		}
+	}
+}
+
+func TestTLSNextProtos(t *testing.T) {
+	v := netflag.NewTLSNextProtos("http/1.1")
+	if err := v.Set("h2, http/1.1"); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"h2", "http/1.1"}, v.Value()); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if err := v.Set(string(make([]byte, 256))); err == nil {
+		t.Error("expected error")
+	}
+}
+
+// testClientTLSConfig returns the result of TLSConfig of Client parsing args.
+func testClientTLSConfig(args ...string) (cfg *tls.Config, err error) {
+	client := netflag.NewClient(clix.FlagPrefix("NETFLAG_TEST_"))
+	app := cli.NewApp()
+	app.Flags = client.Flags()
+	app.Before = client.Before
+	app.Action = func(c *cli.Context) (err error) {
+		cfg, err = client.TLSConfig()
+		return
+	}
+	err = app.Run(append([]string{"test", "--addr", "localhost:0"}, args...))
+	return
+}
+
+func TestTLSParamsFlags(t *testing.T) {
+	serverCfg, err := testServerTLSConfig(context.Background(),
+		"--tls-gen-cert",
+		"--tls-max-version", "1.2",
+		"--tls-cipher-suites", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+		"--tls-curves", "P-384",
+		"--tls-alpn", "h2,http/1.1",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCfg, err := testClientTLSConfig(
+		"--tls-skip-verify",
+		"--tls-cipher-suites", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		"--tls-alpn", "http/1.1,h2",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, s := net.Pipe()
+	defer c.Close()
+	defer s.Close()
+	server := tls.Server(s, serverCfg)
+	go func() { _ = server.Handshake() }()
+	client := tls.Client(c, clientCfg)
+	if err := client.Handshake(); err != nil {
+		t.Fatal(err)
+	}
+	got := client.ConnectionState()
+	if got.CipherSuite != tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384 {
+		t.Errorf("unexpected cipher suite %s", tls.CipherSuiteName(got.CipherSuite))
+	}
+	if got.NegotiatedProtocol != "h2" {
+		t.Errorf("unexpected protocol %q", got.NegotiatedProtocol)
+	}
+
+	cases := [][]string{
+		{"--tls-min-version", "1.3", "--tls-max-version", "1.2"},
+		{"--tls-min-version", "1.3", "--tls-cipher-suites", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+		{"--tls-max-version", "1.2", "--tls-curves", "X25519MLKEM768"},
+	}
+	for _, args := range cases {
+		if _, err := testClientTLSConfig(args...); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+		if _, err := testServerTLSConfig(context.Background(), append([]string{"--tls-gen-cert"}, args...)...); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package netflag
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// TLSClientAuth wraps a tls.ClientAuthType to satisfy flag.Value.
+//
+// The zero value is the default policy, which is tls.RequireAndVerifyClientCert
+// if any CA for client auth is given, otherwise tls.NoClientCert.
+type TLSClientAuth struct {
+	auth tls.ClientAuthType
+	set  bool
+}
+
+// Note: cli.Generic is equivalent to flag.Value.
+var _ cli.Generic = (*TLSClientAuth)(nil)
+
+// NewTLSClientAuth creates a *TLSClientAuth with a default value.
+func NewTLSClientAuth(value tls.ClientAuthType) *TLSClientAuth {
+	return &TLSClientAuth{auth: value, set: true}
+}
+
+// tlsClientAuthNames is the names of tls.ClientAuthType.
+var tlsClientAuthNames = []struct {
+	name string
+	auth tls.ClientAuthType
+}{
+	{"none", tls.NoClientCert},
+	{"request", tls.RequestClientCert},
+	{"require", tls.RequireAnyClientCert},
+	{"verify-if-given", tls.VerifyClientCertIfGiven},
+	{"require-and-verify", tls.RequireAndVerifyClientCert},
+}
+
+// Set parses value as the name of the client auth policy, sets it.
+func (ca *TLSClientAuth) Set(value string) error {
+	for _, v := range tlsClientAuthNames {
+		if v.name == value {
+			ca.auth = v.auth
+			ca.set = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a client auth policy", value)
+}
+
+// String returns a readable representation of this value (for usage defaults)
+func (ca *TLSClientAuth) String() string {
+	if !ca.set {
+		return ""
+	}
+	for _, v := range tlsClientAuthNames {
+		if v.auth == ca.auth {
+			return v.name
+		}
+	}
+	return ""
+}
+
+// Value returns tls.ClientAuthType set by this flag.
+// hasCA is used to decide the default policy.
+func (ca *TLSClientAuth) Value(hasCA bool) tls.ClientAuthType {
+	if ca.set {
+		return ca.auth
+	}
+	if hasCA {
+		return tls.RequireAndVerifyClientCert
+	}
+	return tls.NoClientCert
+}
+
+// verifiesClientCert returns true if auth verifies the client certificate
+// given.
+func verifiesClientCert(auth tls.ClientAuthType) bool {
+	return auth == tls.VerifyClientCertIfGiven || auth == tls.RequireAndVerifyClientCert
+}
@@ -0,0 +1,307 @@
+package netflag_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/takumakei/go-urfave-cli/clix"
+	"github.com/takumakei/go-urfave-cli/netflag"
+	"github.com/urfave/cli/v2"
+)
+
+// testServerTLSConfig returns the result of TLSConfigContext of Server
+// parsing args.
+func testServerTLSConfig(ctx context.Context, args ...string) (cfg *tls.Config, err error) {
+	server := netflag.NewServer(clix.FlagPrefix("NETFLAG_TEST_"))
+	app := cli.NewApp()
+	app.Flags = server.Flags()
+	app.Before = server.Before
+	app.Action = func(c *cli.Context) (err error) {
+		cfg, err = server.TLSConfigContext(ctx)
+		return
+	}
+	err = app.Run(append([]string{"test", "--addr", "localhost:0"}, args...))
+	return
+}
+
+// testHandshake returns the result of the handshake of the server with cfg
+// and the client with certs.
+func testHandshake(cfg *tls.Config, certs ...tls.Certificate) error {
+	c, s := net.Pipe()
+	defer c.Close()
+	defer s.Close()
+	client := tls.Client(c, &tls.Config{
+		Certificates:       certs,
+		InsecureSkipVerify: true,
+	})
+	go func() {
+		// reads the alert of the server that rejects the certificate.
+		if client.Handshake() == nil {
+			_, _ = client.Read(make([]byte, 1))
+		}
+	}()
+	return tls.Server(s, cfg).Handshake()
+}
+
+func TestTLSClientAuth(t *testing.T) {
+	names := []string{"none", "request", "require", "verify-if-given", "require-and-verify"}
+	for _, name := range names {
+		v := &netflag.TLSClientAuth{}
+		if err := v.Set(name); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(name, v.String()); diff != "" {
+			t.Errorf("-want +got\n%s", diff)
+		}
+	}
+	if err := (&netflag.TLSClientAuth{}).Set("verify"); err == nil {
+		t.Error("expected error")
+	}
+
+	var v netflag.TLSClientAuth
+	if diff := cmp.Diff("", v.String()); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if got := v.Value(true); got != tls.RequireAndVerifyClientCert {
+		t.Errorf("want RequireAndVerifyClientCert, got %v", got)
+	}
+	if got := v.Value(false); got != tls.NoClientCert {
+		t.Errorf("want NoClientCert, got %v", got)
+	}
+	if got := netflag.NewTLSClientAuth(tls.VerifyClientCertIfGiven).Value(false); got != tls.VerifyClientCertIfGiven {
+		t.Errorf("want VerifyClientCertIfGiven, got %v", got)
+	}
+}
+
+func TestClientAllowList(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "alice", Organization: []string{"example"}},
+		DNSNames:       []string{"alice.example.com"},
+		EmailAddresses: []string{"alice@example.com"},
+		URIs:           []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/web"}},
+	}
+	cases := []struct {
+		Name string
+		Want bool
+	}{
+		{"alice", true},
+		{"cn:alice", true},
+		{"dn:CN=alice,O=example", true},
+		{"alice.example.com", true},
+		{"dns:alice.example.com", true},
+		{"cn:alice.example.com", false},
+		{"email:alice@example.com", true},
+		{"spiffe://example.org/web", true},
+		{"uri:spiffe://example.org/web", true},
+		{"spiffe://example.org/api", false},
+		{"bob", false},
+		{"cn:", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := (netflag.ClientAllowList{c.Name}).Allows(cert); got != c.Want {
+			t.Errorf("%q: want %v, got %v", c.Name, c.Want, got)
+		}
+	}
+
+	allow := netflag.ClientAllowList{"bob", "alice"}
+	if err := allow.VerifyPeerCertificate(nil, [][]*x509.Certificate{{cert}}); err != nil {
+		t.Error(err)
+	}
+	if err := allow.VerifyPeerCertificate(nil, nil); !errors.Is(err, netflag.ErrNotAllowed) {
+		t.Errorf("want ErrNotAllowed, got %v", err)
+	}
+	allow = netflag.ClientAllowList{"bob"}
+	if err := allow.VerifyPeerCertificate(nil, [][]*x509.Certificate{{cert}}); !errors.Is(err, netflag.ErrNotAllowed) {
+		t.Errorf("want ErrNotAllowed, got %v", err)
+	}
+}
+
+// issueSPIFFE returns the certificate with the SPIFFE ID issued by ca.
+func (ca *testCA) issueSPIFFE(t *testing.T, id string) tls.Certificate {
+	t.Helper()
+	u, err := url.Parse(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := ca.cert.PrivateKey.(crypto.Signer)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{u},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.leaf, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestServerClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+
+	ca := newTestCA(t, "ca")
+	ca.writeCert(t, caFile)
+	alice, _ := ca.issue(t, "alice")
+	web := ca.issueSPIFFE(t, "spiffe://example.org/web")
+	mallory, _ := newTestCA(t, "ca").issue(t, "mallory")
+
+	t.Run("default", func(t *testing.T) {
+		cfg, err := testServerTLSConfig(context.Background(), "--tls-gen-cert", "--tls-ca", caFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+			t.Fatalf("want RequireAndVerifyClientCert, got %v", cfg.ClientAuth)
+		}
+		if err := testHandshake(cfg); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("verify-if-given", func(t *testing.T) {
+		cfg, err := testServerTLSConfig(context.Background(),
+			"--tls-gen-cert",
+			"--tls-ca", caFile,
+			"--tls-client-auth", "verify-if-given",
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cfg); err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cfg, alice); err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cfg, mallory); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		cfg, err := testServerTLSConfig(context.Background(),
+			"--tls-gen-cert",
+			"--tls-ca", caFile,
+			"--tls-client-allow", "spiffe://example.org/web",
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cfg, web); err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cfg, alice); !errors.Is(err, netflag.ErrNotAllowed) {
+			t.Fatalf("want ErrNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("allow-if-given", func(t *testing.T) {
+		cfg, err := testServerTLSConfig(context.Background(),
+			"--tls-gen-cert",
+			"--tls-ca", caFile,
+			"--tls-client-auth", "verify-if-given",
+			"--tls-client-allow", "spiffe://example.org/web",
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cfg, web); err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cfg); !errors.Is(err, netflag.ErrNotAllowed) {
+			t.Fatalf("want ErrNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("allow-without-validate", func(t *testing.T) {
+		server := netflag.NewServer(clix.FlagPrefix("NETFLAG_TEST_"))
+		app := cli.NewApp()
+		app.Flags = server.Flags()
+		app.Action = func(c *cli.Context) error {
+			_, err := server.TLSConfig()
+			return err
+		}
+		err := app.Run([]string{"test", "--addr", "localhost:0",
+			"--tls-gen-cert",
+			"--tls-ca", caFile,
+			"--tls-client-auth", "require",
+			"--tls-client-allow", "alice",
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := [][]string{
+			{"--tls-gen-cert", "--tls-client-auth", "verify-if-given"},
+			{"--tls-gen-cert", "--tls-client-allow", "alice"},
+			{"--tls-gen-cert", "--tls-ca", caFile, "--tls-client-auth", "require", "--tls-client-allow", "alice"},
+		}
+		for _, args := range cases {
+			if _, err := testServerTLSConfig(context.Background(), args...); err == nil {
+				t.Errorf("%v: expected error", args)
+			}
+		}
+	})
+}
+
+func TestValidateAll(t *testing.T) {
+	prefix := clix.FlagPrefix("NETFLAG_TEST_")
+	server := netflag.NewServerName(prefix, "srv")
+	client := netflag.NewClientName(prefix, "cli")
+	app := cli.NewApp()
+	app.Flags = clix.Flags(server.Flags(), client.Flags())
+	app.Before = clix.ValidateAll(server, client)
+	app.Action = func(*cli.Context) error { return nil }
+	err := app.Run([]string{"test",
+		"--srv-addr", "localhost:0",
+		"--srv-tls-cert", "cert.pem",
+		"--srv-tls-gen-cert",
+		"--srv-tls-min-version", "1.3",
+		"--srv-tls-max-version", "1.2",
+		"--cli-addr", "localhost:70000",
+		"--cli-tls-cert-key", "key.pem",
+	})
+	if err == nil {
+		t.Fatal("no error")
+	}
+	if !errors.Is(err, clix.ErrForbiddenFlags) {
+		t.Errorf("errors.Is(err, clix.ErrForbiddenFlags)=false")
+	}
+	var errs clix.Errors
+	if !errors.As(err, &errs) || len(errs) != 7 {
+		t.Fatalf("want 7 errors, got %v", err)
+	}
+	want := []string{
+		"forbidden flags are set: --srv-tls-cert [$NETFLAG_TEST_SRV_TLS_CERT, $NETFLAG_TEST_SRV_TLSCRT] (forbidden if --srv-tls-gen-cert is true)",
+		"--srv-tls-cert [$NETFLAG_TEST_SRV_TLS_CERT, $NETFLAG_TEST_SRV_TLSCRT] (arg:--srv-tls-cert): 1 given for 0 of --srv-tls-cert-key",
+		"--srv-tls-cert [$NETFLAG_TEST_SRV_TLS_CERT, $NETFLAG_TEST_SRV_TLSCRT] (arg:--srv-tls-cert): stat cert.pem: no such file or directory",
+		"--srv-tls-min-version [$NETFLAG_TEST_SRV_TLS_MIN_VERSION, $NETFLAG_TEST_SRV_TLSMIN] (arg:--srv-tls-min-version): 1.3 is greater than 1.2 of --srv-tls-max-version",
+		"--cli-tls-cert-key [$NETFLAG_TEST_CLI_TLS_CERT_KEY, $NETFLAG_TEST_CLI_TLSKEY] (arg:--cli-tls-cert-key): 1 given for 0 of --cli-tls-cert",
+		"--cli-address [$NETFLAG_TEST_CLI_ADDRESS, $NETFLAG_TEST_CLI_ADDR] (arg:--cli-address): 70000 is out of range [0, 65535]",
+		"--cli-tls-cert-key [$NETFLAG_TEST_CLI_TLS_CERT_KEY, $NETFLAG_TEST_CLI_TLSKEY] (arg:--cli-tls-cert-key): stat key.pem: no such file or directory",
+	}
+	for i, e := range errs {
+		if e.Error() != want[i] {
+			t.Errorf("errs[%d]=%q, want %q", i, e, want[i])
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package netflag
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// TLSCurves wraps a list of tls.CurveID to satisfy flag.Value.
+type TLSCurves struct {
+	ids []tls.CurveID
+}
+
+// Note: cli.Generic is equivalent to flag.Value.
+var _ cli.Generic = (*TLSCurves)(nil)
+
+// NewTLSCurves creates a *TLSCurves with a default value.
+func NewTLSCurves(value ...tls.CurveID) *TLSCurves {
+	return &TLSCurves{ids: value}
+}
+
+// tlsCurveNames is the names of tls.CurveID.
+var tlsCurveNames = []struct {
+	name string
+	id   tls.CurveID
+}{
+	{"X25519", tls.X25519},
+	{"P-256", tls.CurveP256},
+	{"P-384", tls.CurveP384},
+	{"P-521", tls.CurveP521},
+	{"X25519MLKEM768", tls.X25519MLKEM768},
+}
+
+// Set parses value as the comma separated names of the curves, replaces the
+// value with them.
+func (tc *TLSCurves) Set(value string) error {
+	var ids []tls.CurveID
+	for _, name := range splitList(value) {
+		id, ok := lookupCurve(name)
+		if !ok {
+			return fmt.Errorf("%s is not a curve", name)
+		}
+		ids = append(ids, id)
+	}
+	tc.ids = ids
+	return nil
+}
+
+// lookupCurve returns the tls.CurveID named name.
+// The names of tls.CurveID.String() such as "CurveP256" are also accepted.
+func lookupCurve(name string) (tls.CurveID, bool) {
+	for _, v := range tlsCurveNames {
+		if strings.EqualFold(v.name, name) || v.id.String() == name {
+			return v.id, true
+		}
+	}
+	return 0, false
+}
+
+// String returns a readable representation of this value (for usage defaults)
+func (tc *TLSCurves) String() string {
+	names := make([]string, len(tc.ids))
+	for i, id := range tc.ids {
+		names[i] = curveName(id)
+	}
+	return strings.Join(names, ",")
+}
+
+// curveName returns the name of id.
+func curveName(id tls.CurveID) string {
+	for _, v := range tlsCurveNames {
+		if v.id == id {
+			return v.name
+		}
+	}
+	return id.String()
+}
+
+// Value returns the curves set by this flag, nil for the default of
+// crypto/tls.
+func (tc *TLSCurves) Value() []tls.CurveID {
+	return tc.ids
+}
+
+// Validate returns non-nil error if any of the curves supports none of the
+// TLS versions from min to max, or no curve supports the TLS versions older
+// than 1.3 in the range.
+func (tc *TLSCurves) Validate(min, max uint16) error {
+	if len(tc.ids) == 0 {
+		return nil
+	}
+	classic := false
+	for _, id := range tc.ids {
+		if !isTLS13OnlyCurve(id) {
+			classic = true
+		} else if max < tls.VersionTLS13 {
+			return fmt.Errorf("%s requires TLS 1.3", curveName(id))
+		}
+	}
+	if !classic && min < tls.VersionTLS13 {
+		return fmt.Errorf("no curve for TLS %s", NewTLSVersion(min))
+	}
+	return nil
+}
+
+// isTLS13OnlyCurve returns true if id is available only with TLS 1.3.
+func isTLS13OnlyCurve(id tls.CurveID) bool {
+	return id == tls.X25519MLKEM768
+}
@@ -0,0 +1,44 @@
+package netflag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// TLSNextProtos wraps a list of the ALPN protocols to satisfy flag.Value.
+type TLSNextProtos struct {
+	protos []string
+}
+
+// Note: cli.Generic is equivalent to flag.Value.
+var _ cli.Generic = (*TLSNextProtos)(nil)
+
+// NewTLSNextProtos creates a *TLSNextProtos with a default value.
+func NewTLSNextProtos(value ...string) *TLSNextProtos {
+	return &TLSNextProtos{protos: value}
+}
+
+// Set parses value as the comma separated ALPN protocols, replaces the value
+// with them.
+func (np *TLSNextProtos) Set(value string) error {
+	protos := splitList(value)
+	for _, proto := range protos {
+		if len(proto) > 255 {
+			return fmt.Errorf("%s is too long for ALPN protocol", proto)
+		}
+	}
+	np.protos = protos
+	return nil
+}
+
+// String returns a readable representation of this value (for usage defaults)
+func (np *TLSNextProtos) String() string {
+	return strings.Join(np.protos, ",")
+}
+
+// Value returns the ALPN protocols set by this flag.
+func (np *TLSNextProtos) Value() []string {
+	return np.protos
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 
+	"github.com/takumakei/go-urfave-cli/clix"
 	"github.com/urfave/cli/v2"
 )
 
@@ -56,3 +57,43 @@ func (tv *TLSVersion) String() string {
 func (tv *TLSVersion) Value() uint16 {
 	return tv.ver
 }
+
+// checkTLSFlags returns the constraint that the TLS versions from min to max
+// are not empty, and the cipher suites and the curves are supported by them.
+// The problems are reported as *clix.FlagError.
+func checkTLSFlags(min, max *cli.GenericFlag, suites, curves *cli.GenericFlag) clix.Constraint {
+	return func(fs clix.FlagSet) error {
+		minVer := min.Value.(*TLSVersion).Value()
+		maxVer := max.Value.(*TLSVersion).Value()
+		if minVer > maxVer {
+			err := fmt.Errorf("%s is greater than %s of --%s", min.Value, max.Value, max.Name)
+			return &clix.FlagError{Flag: min, Source: fs.Source(min), Err: err}
+		}
+		var errs clix.Errors
+		if err := suites.Value.(*TLSCipherSuites).Validate(minVer, maxVer); err != nil {
+			errs = append(errs, &clix.FlagError{Flag: suites, Source: fs.Source(suites), Err: err})
+		}
+		if err := curves.Value.(*TLSCurves).Validate(minVer, maxVer); err != nil {
+			errs = append(errs, &clix.FlagError{Flag: curves, Source: fs.Source(curves), Err: err})
+		}
+		return errs.Err()
+	}
+}
+
+// checkKeyPairs returns the constraint that the number of certs and keys
+// match. The problem is reported as *clix.FlagError of keys, or certs if no
+// key is given.
+func checkKeyPairs(certs, keys *cli.StringSliceFlag) clix.Constraint {
+	return func(fs clix.FlagSet) error {
+		flag, other := keys, certs
+		n, m := len(keys.Destination.Value()), len(certs.Destination.Value())
+		if n == m {
+			return nil
+		}
+		if n == 0 {
+			flag, other, n, m = certs, keys, m, n
+		}
+		err := fmt.Errorf("%d given for %d of --%s", n, m, other.Name)
+		return &clix.FlagError{Flag: flag, Source: fs.Source(flag), Err: err}
+	}
+}
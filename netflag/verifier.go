@@ -0,0 +1,329 @@
+package netflag
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ErrRevoked represents an error where the client certificate is revoked.
+var ErrRevoked = errors.New("certificate is revoked")
+
+// ErrStaleCRL represents an error where every CRL of the issuer of the
+// client certificate is past its next update.
+var ErrStaleCRL = errors.New("CRL is stale")
+
+// ClientVerifier holds the pool of the client CAs and the certificate
+// revocation lists loaded from the files, and reloads them when the files
+// change.
+type ClientVerifier struct {
+	// HTTPClient is used to send the OCSP requests.
+	// A client with 10 seconds timeout is used if nil.
+	HTTPClient *http.Client
+
+	// OCSPSoftFail accepts the client certificate if the OCSP responder is
+	// unavailable or answers an invalid response, instead of rejecting it.
+	// The certificate is still rejected if it is revoked or unknown to the
+	// OCSP responder.
+	OCSPSoftFail bool
+
+	caFiles  []string
+	crlFiles []string
+	ocspURL  string
+	onReload func(ReloadEvent)
+
+	// state is *verifierState.
+	state atomic.Value
+
+	mu     sync.Mutex
+	digest []byte
+
+	// ocspCache is the OCSP responses by ocspCacheKey, kept until their
+	// next update.
+	ocspMu    sync.Mutex
+	ocspCache map[string]*ocsp.Response
+}
+
+// verifierState is the set of the files loaded at once.
+type verifierState struct {
+	pool *x509.CertPool
+	crls []*x509.RevocationList
+}
+
+// NewClientVerifier returns *ClientVerifier with the CAs loaded from caFiles
+// and the CRLs loaded from crlFiles. If ocspURL is not empty, the status of
+// the client certificate is queried to the OCSP responder on each handshake
+// unless the previous response is cached until its next update.
+// onReload is called with the result of each reload after the first load,
+// may be nil.
+func NewClientVerifier(caFiles, crlFiles []string, ocspURL string, onReload func(ReloadEvent)) (*ClientVerifier, error) {
+	if len(caFiles) == 0 {
+		return nil, errors.New("no CA file")
+	}
+	v := &ClientVerifier{
+		caFiles:  caFiles,
+		crlFiles: crlFiles,
+		ocspURL:  ocspURL,
+		onReload: onReload,
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.digest = digestFiles(v.caFiles, v.crlFiles)
+	state, err := v.load()
+	if err != nil {
+		return nil, err
+	}
+	v.state.Store(state)
+	return v, nil
+}
+
+// ClientCAs returns the pool of the CAs in use.
+func (v *ClientVerifier) ClientCAs() *x509.CertPool {
+	return v.state.Load().(*verifierState).pool
+}
+
+// Reload loads the files, replaces the CAs and the CRLs in use if all of
+// them are valid, and calls onReload with the result.
+func (v *ClientVerifier) Reload() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.digest = digestFiles(v.caFiles, v.crlFiles)
+	return v.reload()
+}
+
+// ReloadIfChanged calls Reload if the content of any file has changed since
+// the last load. The files that failed to load are not retried until they
+// change again.
+func (v *ClientVerifier) ReloadIfChanged() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	digest := digestFiles(v.caFiles, v.crlFiles)
+	if string(digest) == string(v.digest) {
+		return nil
+	}
+	v.digest = digest
+	return v.reload()
+}
+
+// Watch calls ReloadIfChanged every interval until ctx is done.
+func (v *ClientVerifier) Watch(ctx context.Context, interval time.Duration) {
+	watch(ctx, interval, v.ReloadIfChanged)
+}
+
+// VerifyPeerCertificate returns non-nil error if any certificate in
+// verifiedChains is revoked by the CRLs, every CRL of its issuer is stale, or
+// the leaf certificate is not good according to the OCSP responder.
+// VerifyPeerCertificate is intended to be used as
+// tls.Config.VerifyPeerCertificate along with tls.RequireAndVerifyClientCert.
+func (v *ClientVerifier) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	state := v.state.Load().(*verifierState)
+	now := time.Now()
+	for _, chain := range verifiedChains {
+		for i := 0; i+1 < len(chain); i++ {
+			if err := state.checkCRL(chain[i], chain[i+1], now); err != nil {
+				return err
+			}
+		}
+		if len(v.ocspURL) > 0 && len(chain) > 1 {
+			if err := v.checkOCSP(chain[0], chain[1], now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCRL returns ErrRevoked if cert is listed in the CRLs of issuer,
+// ErrStaleCRL if all the CRLs of issuer are past their next update at now.
+// The stale CRLs are ignored if there is a fresh one.
+func (s *verifierState) checkCRL(cert, issuer *x509.Certificate, now time.Time) error {
+	fresh, stale := false, false
+	for _, crl := range s.crls {
+		if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) {
+			continue
+		}
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			continue
+		}
+		if !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate) {
+			stale = true
+			continue
+		}
+		fresh = true
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return fmt.Errorf("%w: %q (serial %s) is listed in the CRL", ErrRevoked, cert.Subject, cert.SerialNumber)
+			}
+		}
+	}
+	if stale && !fresh {
+		return fmt.Errorf("%w: the CRL of %q is past its next update", ErrStaleCRL, issuer.Subject)
+	}
+	return nil
+}
+
+// defaultOCSPClient is used if ClientVerifier.HTTPClient is nil.
+var defaultOCSPClient = &http.Client{Timeout: 10 * time.Second}
+
+// checkOCSP returns nil if the OCSP responder answers that cert is good.
+// It also returns nil if v.OCSPSoftFail is true and the OCSP responder fails
+// to answer.
+func (v *ClientVerifier) checkOCSP(cert, issuer *x509.Certificate, now time.Time) error {
+	res, err := v.queryOCSP(cert, issuer, now)
+	if err != nil {
+		if v.OCSPSoftFail {
+			return nil
+		}
+		return err
+	}
+	switch res.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return fmt.Errorf("%w: %q (serial %s) is revoked by the OCSP responder", ErrRevoked, cert.Subject, cert.SerialNumber)
+	}
+	return fmt.Errorf("status of %q (serial %s) is unknown to the OCSP responder", cert.Subject, cert.SerialNumber)
+}
+
+// queryOCSP returns the OCSP response for cert, cached until its next update.
+func (v *ClientVerifier) queryOCSP(cert, issuer *x509.Certificate, now time.Time) (*ocsp.Response, error) {
+	key := ocspCacheKey(cert, issuer)
+	v.ocspMu.Lock()
+	res, ok := v.ocspCache[key]
+	v.ocspMu.Unlock()
+	if ok && now.Before(res.NextUpdate) {
+		return res, nil
+	}
+	res, err := v.requestOCSP(cert, issuer)
+	if err != nil {
+		return nil, err
+	}
+	v.ocspMu.Lock()
+	defer v.ocspMu.Unlock()
+	for k, r := range v.ocspCache {
+		if !now.Before(r.NextUpdate) {
+			delete(v.ocspCache, k)
+		}
+	}
+	if now.Before(res.NextUpdate) {
+		if v.ocspCache == nil {
+			v.ocspCache = make(map[string]*ocsp.Response)
+		}
+		v.ocspCache[key] = res
+	}
+	return res, nil
+}
+
+// ocspCacheKey returns the key of the OCSP response for cert.
+func ocspCacheKey(cert, issuer *x509.Certificate) string {
+	return string(issuer.RawSubject) + "\x00" + string(issuer.RawSubjectPublicKeyInfo) + "\x00" + cert.SerialNumber.String()
+}
+
+// requestOCSP returns the OCSP response for cert from the OCSP responder.
+func (v *ClientVerifier) requestOCSP(cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := v.HTTPClient
+	if client == nil {
+		client = defaultOCSPClient
+	}
+	resp, err := client.Post(v.ocspURL, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OCSP responder, %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query OCSP responder, %s", resp.Status)
+	}
+	p, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OCSP responder, %w", err)
+	}
+	res, err := ocsp.ParseResponseForCert(p, cert, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCSP response, %w", err)
+	}
+	return res, nil
+}
+
+// reload loads the files, replaces the state in use on success,
+// and calls onReload.
+func (v *ClientVerifier) reload() error {
+	state, err := v.load()
+	if err == nil {
+		v.state.Store(state)
+	}
+	if v.onReload != nil {
+		files := append(append([]string(nil), v.caFiles...), v.crlFiles...)
+		v.onReload(ReloadEvent{Files: files, Err: err})
+	}
+	return err
+}
+
+// load returns the state loaded from the files.
+func (v *ClientVerifier) load() (*verifierState, error) {
+	state := &verifierState{pool: x509.NewCertPool()}
+	for _, ca := range v.caFiles {
+		p, err := os.ReadFile(ca)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA %q, %w", ca, err)
+		}
+		if ok := state.pool.AppendCertsFromPEM(p); !ok {
+			return nil, fmt.Errorf("failed to load CA %q", ca)
+		}
+	}
+	for _, file := range v.crlFiles {
+		crls, err := loadCRLs(file)
+		if err != nil {
+			return nil, err
+		}
+		state.crls = append(state.crls, crls...)
+	}
+	return state, nil
+}
+
+// loadCRLs returns the CRLs in file, which is either PEM or DER encoded.
+func loadCRLs(file string) ([]*x509.RevocationList, error) {
+	p, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRL %q, %w", file, err)
+	}
+	var crls []*x509.RevocationList
+	rest := p
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			continue
+		}
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CRL %q, %w", file, err)
+		}
+		crls = append(crls, crl)
+	}
+	if len(crls) > 0 {
+		return crls, nil
+	}
+	crl, err := x509.ParseRevocationList(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CRL %q, %w", file, err)
+	}
+	return []*x509.RevocationList{crl}, nil
+}
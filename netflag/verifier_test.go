@@ -0,0 +1,349 @@
+package netflag_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/takumakei/go-cert4now"
+	"github.com/takumakei/go-urfave-cli/netflag"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCA struct {
+	cert tls.Certificate
+	leaf *x509.Certificate
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	cert, err := cert4now.Generate(
+		cert4now.CommonName(name),
+		cert4now.ECDSA(elliptic.P256()),
+		cert4now.IsCA(true),
+		cert4now.KeyUsage(x509.KeyUsageCertSign|x509.KeyUsageCRLSign|x509.KeyUsageDigitalSignature),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, leaf: leaf}
+}
+
+func (ca *testCA) issue(t *testing.T, name string) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+	cert, err := cert4now.Generate(
+		cert4now.CommonName(name),
+		cert4now.ECDSA(elliptic.P256()),
+		cert4now.Authority(ca.cert),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, leaf
+}
+
+func (ca *testCA) writeCert(t *testing.T, file string) {
+	t.Helper()
+	if err := cert4now.WriteCertificateFile(file, ca.cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (ca *testCA) writeCRL(t *testing.T, file string, number int64, revoked ...*x509.Certificate) {
+	t.Helper()
+	ca.writeCRLAt(t, file, number, time.Now(), revoked...)
+}
+
+func (ca *testCA) writeCRLAt(t *testing.T, file string, number int64, thisUpdate time.Time, revoked ...*x509.Certificate) {
+	t.Helper()
+	tmpl := &x509.RevocationList{
+		Number:     big.NewInt(number),
+		ThisUpdate: thisUpdate,
+		NextUpdate: thisUpdate.Add(time.Hour),
+	}
+	for _, cert := range revoked {
+		tmpl.RevokedCertificateEntries = append(tmpl.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: time.Now(),
+		})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, tmpl, ca.leaf, ca.cert.PrivateKey.(crypto.Signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+	if err := os.WriteFile(file, p, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientVerifierCRL(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	crlFile := filepath.Join(dir, "crl.pem")
+
+	ca := newTestCA(t, "ca")
+	ca.writeCert(t, caFile)
+	ca.writeCRL(t, crlFile, 1)
+	_, alice := ca.issue(t, "alice")
+	_, bob := ca.issue(t, "bob")
+
+	var events []netflag.ReloadEvent
+	v, err := netflag.NewClientVerifier([]string{caFile}, []string{crlFile}, "", func(e netflag.ReloadEvent) {
+		events = append(events, e)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chains := func(cert *x509.Certificate) [][]*x509.Certificate {
+		return [][]*x509.Certificate{{cert, ca.leaf}}
+	}
+	if err := v.VerifyPeerCertificate(nil, chains(alice)); err != nil {
+		t.Fatal(err)
+	}
+
+	// alice is revoked
+	ca.writeCRL(t, crlFile, 2, alice)
+	if err := v.ReloadIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifyPeerCertificate(nil, chains(alice)); !errors.Is(err, netflag.ErrRevoked) {
+		t.Fatalf("want ErrRevoked, got %v", err)
+	}
+	if err := v.VerifyPeerCertificate(nil, chains(bob)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the CRL of another CA is ignored
+	other := newTestCA(t, "ca")
+	other.writeCRL(t, crlFile, 3, bob)
+	if err := v.ReloadIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifyPeerCertificate(nil, chains(bob)); err != nil {
+		t.Fatal(err)
+	}
+
+	// broken, the previous state is kept
+	if err := os.WriteFile(crlFile, []byte("broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.ReloadIfChanged(); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := v.VerifyPeerCertificate(nil, chains(bob)); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[2].Err == nil {
+		t.Fatalf("unexpected events %v", events)
+	}
+
+	// the CA is replaced
+	other.writeCert(t, caFile)
+	ca.writeCRL(t, crlFile, 4)
+	if err := v.ReloadIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	opts := x509.VerifyOptions{
+		Roots:     v.ClientCAs(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if _, err := alice.Verify(opts); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClientVerifierOCSP(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+
+	ca := newTestCA(t, "ca")
+	ca.writeCert(t, caFile)
+	_, alice := ca.issue(t, "alice")
+	_, bob := ca.issue(t, "bob")
+	_, carol := ca.issue(t, "carol")
+
+	var requests atomic.Int32
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		p, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req, err := ocsp.ParseRequest(p)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tmpl := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		if req.SerialNumber.Cmp(alice.SerialNumber) == 0 {
+			tmpl.Status = ocsp.Revoked
+			tmpl.RevokedAt = time.Now()
+		}
+		res, err := ocsp.CreateResponse(ca.leaf, ca.leaf, tmpl, ca.cert.PrivateKey.(crypto.Signer))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(res)
+	}))
+	defer responder.Close()
+
+	v, err := netflag.NewClientVerifier([]string{caFile}, nil, responder.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{alice, ca.leaf}}); !errors.Is(err, netflag.ErrRevoked) {
+		t.Fatalf("want ErrRevoked, got %v", err)
+	}
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{bob, ca.leaf}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the responses are cached until their next update
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{alice, ca.leaf}}); !errors.Is(err, netflag.ErrRevoked) {
+		t.Fatalf("want ErrRevoked, got %v", err)
+	}
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{bob, ca.leaf}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("want 2 requests, got %d", n)
+	}
+
+	responder.Close()
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{bob, ca.leaf}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{carol, ca.leaf}}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// soft-fail
+	v.OCSPSoftFail = true
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{carol, ca.leaf}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifyPeerCertificate(nil, [][]*x509.Certificate{{alice, ca.leaf}}); !errors.Is(err, netflag.ErrRevoked) {
+		t.Fatalf("want ErrRevoked, got %v", err)
+	}
+}
+
+func TestClientVerifierStaleCRL(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	crlFile := filepath.Join(dir, "crl.pem")
+
+	ca := newTestCA(t, "ca")
+	ca.writeCert(t, caFile)
+	ca.writeCRLAt(t, crlFile, 1, time.Now().Add(-2*time.Hour))
+	_, alice := ca.issue(t, "alice")
+
+	v, err := netflag.NewClientVerifier([]string{caFile}, []string{crlFile}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chains := [][]*x509.Certificate{{alice, ca.leaf}}
+	if err := v.VerifyPeerCertificate(nil, chains); !errors.Is(err, netflag.ErrStaleCRL) {
+		t.Fatalf("want ErrStaleCRL, got %v", err)
+	}
+
+	// the stale CRL is ignored along with a fresh one
+	stale, err := os.ReadFile(crlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca.writeCRL(t, crlFile, 2)
+	fresh, err := os.ReadFile(crlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(crlFile, append(stale, fresh...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.ReloadIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifyPeerCertificate(nil, chains); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerClientAuthCRL(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	crlFile := filepath.Join(dir, "crl.pem")
+
+	ca := newTestCA(t, "ca")
+	ca.writeCert(t, caFile)
+	ca.writeCRL(t, crlFile, 1)
+	aliceCert, alice := ca.issue(t, "alice")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := testServerTLSConfig(ctx,
+		"--tls-gen-cert",
+		"--tls-ca", caFile,
+		"--tls-crl", crlFile,
+		"--tls-reload-interval", "10ms",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handshake := func() error {
+		return testHandshake(cfg, aliceCert)
+	}
+	if err := handshake(); err != nil {
+		t.Fatal(err)
+	}
+
+	ca.writeCRL(t, crlFile, 2, alice)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := handshake()
+		if errors.Is(err, netflag.ErrRevoked) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("want ErrRevoked, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerBeforeCRLRequiresCA(t *testing.T) {
+	if _, err := testServerTLSConfig(context.Background(), "--tls-crl", "crl.pem"); err == nil {
+		t.Fatal("expected error")
+	}
+}
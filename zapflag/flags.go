@@ -32,20 +32,28 @@ func New(prefix clix.FlagPrefix) *Flags {
 	return NewName("", prefix)
 }
 
-// NewName returns a *Flags.
+// NewName returns NewNaming(name, clix.Naming{Prefix: prefix}).
 func NewName(name string, prefix clix.FlagPrefix) *Flags {
+	return NewNaming(name, clix.Naming{Prefix: prefix})
+}
+
+// NewNaming returns a *Flags whose flags are named by naming.
+//
+// The alias of "log-sampling-thereafter" is "lsth" unless naming.Alias is
+// set, since the initials collide with the ones of "log-stack-trace".
+func NewNaming(name string, naming clix.Naming) *Flags {
 	var (
-		logDevelopment        = clix.NewFlagName(prefix, name, "log-development")
-		logLevel              = clix.NewFlagName(prefix, name, "log-level")
-		logWithCaller         = clix.NewFlagName(prefix, name, "log-with-caller")
-		logStackTrace         = clix.NewFlagName(prefix, name, "log-stack-trace")
-		logStackTraceLv       = clix.NewFlagName(prefix, name, "log-stack-trace-level")
-		logDateFormat         = clix.NewFlagName(prefix, name, "log-date-format")
-		logFields             = clix.NewFlagName(prefix, name, "log-field")
-		logPaths              = clix.NewFlagName(prefix, name, "log-path")
-		logErrPaths           = clix.NewFlagName(prefix, name, "log-err-path")
-		logSamplingInitial    = clix.NewFlagName(prefix, name, "log-sampling-initial")
-		logSamplingThereafter = clix.NewFlagNameAlias(prefix, name, "log-sampling-thereafter", "lsth")
+		logDevelopment        = naming.NewFlagName(name, "log-development")
+		logLevel              = naming.NewFlagName(name, "log-level")
+		logWithCaller         = naming.NewFlagName(name, "log-with-caller")
+		logStackTrace         = naming.NewFlagName(name, "log-stack-trace")
+		logStackTraceLv       = naming.NewFlagName(name, "log-stack-trace-level")
+		logDateFormat         = naming.NewFlagName(name, "log-date-format")
+		logFields             = naming.NewFlagName(name, "log-field")
+		logPaths              = naming.NewFlagName(name, "log-path")
+		logErrPaths           = naming.NewFlagName(name, "log-err-path")
+		logSamplingInitial    = naming.NewFlagName(name, "log-sampling-initial")
+		logSamplingThereafter = naming.NewFlagNameDefault(name, "log-sampling-thereafter", "lsth")
 	)
 
 	return &Flags{
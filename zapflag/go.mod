@@ -1,14 +1,26 @@
 module github.com/takumakei/go-urfave-cli/zapflag
 
-go 1.16
+go 1.21
 
 require (
-	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
 	github.com/google/go-cmp v0.5.5
-	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/takumakei/go-exit v0.0.0-20210429095029-8c3e71abac7f
-	github.com/takumakei/go-urfave-cli/clix v0.0.0-20210515030343-d757dd16f8e6
+	github.com/takumakei/go-urfave-cli/clix v0.1.0
 	github.com/takumakei/go-zapx v0.0.0-20210508083520-18bf9667e35e
 	github.com/urfave/cli/v2 v2.3.0
 	go.uber.org/zap v1.16.0
 )
+
+require (
+	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	go.uber.org/atomic v1.7.0 // indirect
+	go.uber.org/multierr v1.7.0 // indirect
+	gopkg.in/yaml.v2 v2.2.3 // indirect
+)
+
+// The clix API used here (Naming) is released in clix/v0.1.0, which must be
+// tagged before this module. The replace directive only applies to the builds
+// in this repository.
+replace github.com/takumakei/go-urfave-cli/clix => ../clix